@@ -1,7 +1,7 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
 // Copyright (C) 2018 Canonical Ltd
-// Copyright (C) 2018-2019 IOTech Ltd
+// Copyright (C) 2018-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -19,60 +19,75 @@ import (
 
 // processAsyncResults processes readings that are pushed from
 // a DS implementation. Each is reading is optionally transformed
-// before being pushed to Core Data.
-func processAsyncResults() {
-	for !svc.stopped {
-		acv := <-svc.asyncCh
-		readings := make([]contract.Reading, 0, len(acv.CommandValues))
+// before being pushed to Core Data. It's called by the workers of
+// the async.Pool, and the readings of a Device are processed in order.
+func processAsyncResults(acv *dsModels.AsyncValues) {
+	readings := make([]contract.Reading, 0, len(acv.CommandValues))
+	qualities := make([]dsModels.Quality, 0, len(acv.CommandValues))
+	mediaTypes := make(map[string]string)
 
-		device, ok := cache.Devices().ForName(acv.DeviceName)
+	device, ok := cache.Devices().ForName(acv.DeviceName)
+	if !ok {
+		common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - recieved Device %s not found in cache", acv.DeviceName))
+		return
+	}
+
+	for _, cv := range acv.CommandValues {
+		// get the device resource associated with the rsp.RO
+		dr, ok := cache.Profiles().DeviceResource(device.Profile.Name, cv.DeviceResourceName)
 		if !ok {
-			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - recieved Device %s not found in cache", acv.DeviceName))
+			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - Device Resource %s not found in Device %s", cv.DeviceResourceName, acv.DeviceName))
 			continue
 		}
 
-		for _, cv := range acv.CommandValues {
-			// get the device resource associated with the rsp.RO
-			dr, ok := cache.Profiles().DeviceResource(device.Profile.Name, cv.DeviceResourceName)
-			if !ok {
-				common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - Device Resource %s not found in Device %s", cv.DeviceResourceName, acv.DeviceName))
-				continue
-			}
-
-			if common.CurrentConfig.Device.DataTransform {
-				err := transformer.TransformReadResult(cv, dr.Properties.Value)
-				if err != nil {
-					common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - CommandValue (%s) transformed failed: %v", cv.String(), err))
-					cv = dsModels.NewStringValue(cv.DeviceResourceName, cv.Origin, fmt.Sprintf("Transformation failed for device resource, with value: %s, property value: %v, and error: %v", cv.String(), dr.Properties.Value, err))
-				}
-			}
-
-			err := transformer.CheckAssertion(cv, dr.Properties.Value.Assertion, &device)
+		if common.CurrentConfig.Device.DataTransform {
+			err := transformer.TransformReadResource(cv, &dr)
 			if err != nil {
-				common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - Assertion failed for device resource: %s, with value: %s and assertion: %s, %v", cv.DeviceResourceName, cv.String(), dr.Properties.Value.Assertion, err))
-				cv = dsModels.NewStringValue(cv.DeviceResourceName, cv.Origin, fmt.Sprintf("Assertion failed for device resource, with value: %s and assertion: %s", cv.String(), dr.Properties.Value.Assertion))
+				common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - CommandValue (%s) transformed failed: %v", cv.String(), err))
+				cv.DegradeQuality(dsModels.QualityBad, dsModels.ReasonTransformFailed)
 			}
+		}
 
-			ro, err := cache.Profiles().ResourceOperation(device.Profile.Name, cv.DeviceResourceName, common.GetCmdMethod)
-			if err != nil {
-				common.LoggingClient.Debug(fmt.Sprintf("processAsyncResults - getting resource operation failed: %s", err.Error()))
-			} else if len(ro.Mappings) > 0 {
-				newCV, ok := transformer.MapCommandValue(cv, ro.Mappings)
-				if ok {
-					cv = newCV
-				} else {
-					common.LoggingClient.Warn(fmt.Sprintf("processAsyncResults - Mapping failed for Device Resource Operation: %s, with value: %s, %v", ro.DeviceCommand, cv.String(), err))
-				}
+		policy, err := transformer.CheckAssertion(cv, &dr, &device)
+		if err != nil && policy == transformer.AssertionPolicyDrop {
+			continue
+		} else if err != nil && policy == transformer.AssertionPolicyDisable {
+			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - Assertion failed for device resource: %s, with value: %s and assertion: %s, %v", cv.DeviceResourceName, cv.String(), dr.Properties.Value.Assertion, err))
+			cv.DegradeQuality(dsModels.QualityBad, dsModels.ReasonAssertionFailed)
+		} else if err != nil {
+			cv.DegradeQuality(dsModels.QualityUncertain, dsModels.ReasonAssertionFailed)
+		}
+
+		ro, err := cache.Profiles().ResourceOperation(device.Profile.Name, cv.DeviceResourceName, common.GetCmdMethod)
+		if err != nil {
+			common.LoggingClient.Debug(fmt.Sprintf("processAsyncResults - getting resource operation failed: %s", err.Error()))
+		} else if len(ro.Mappings) > 0 {
+			newCV, ok := transformer.MapCommandValue(cv, ro.Mappings)
+			if ok {
+				cv = newCV
+			} else {
+				common.LoggingClient.Warn(fmt.Sprintf("processAsyncResults - Mapping failed for Device Resource Operation: %s, with value: %s, %v", ro.DeviceCommand, cv.String(), err))
+				cv.DegradeQuality(dsModels.QualityUncertain, dsModels.ReasonMappingFailed)
 			}
+		}
 
-			reading := common.CommandValueToReading(cv, device.Name, dr.Properties.Value.FloatEncoding)
-			readings = append(readings, *reading)
+		cv, err = transformer.CheckValueLength(cv, common.CurrentConfig.Device.MaxCmdValueLen, common.CurrentConfig.Device.MaxCmdValueLenPolicy)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("processAsyncResults - the reading of Device %s is dropped: %v", acv.DeviceName, err))
+			continue
 		}
 
-		// push to Core Data
-		cevent := contract.Event{Device: device.Name, Readings: readings}
-		event := &dsModels.Event{Event: cevent}
-		event.Origin = common.GetUniqueOrigin()
-		common.SendEvent(event)
+		reading := common.CommandValueToReading(cv, device.Name, dr.Properties.Value.FloatEncoding)
+		readings = append(readings, *reading)
+		qualities = append(qualities, cv.Quality)
+		if mediaType := common.MediaType(cv, dr); mediaType != "" {
+			mediaTypes[reading.Name] = mediaType
+		}
 	}
+
+	// push to Core Data
+	cevent := contract.Event{Device: device.Name, Readings: readings}
+	event := &dsModels.Event{Event: cevent, MediaTypes: mediaTypes, Qualities: qualities}
+	event.Origin = common.GetUniqueOrigin()
+	common.SendEvent(event)
 }
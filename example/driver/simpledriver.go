@@ -33,36 +33,35 @@ type SimpleDriver struct {
 	zRotation    int32
 }
 
-func getImageBytes(imgFile string, buf *bytes.Buffer) error {
+func getImageBytes(imgFile string, buf *bytes.Buffer) (mediaType string, err error) {
 	// Read existing image from file
 	img, err := os.Open(imgFile)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer img.Close()
 
-	// TODO: Attach MediaType property, determine if decoding
-	//  early is required (to optimize edge processing)
+	// TODO: determine if decoding early is required (to optimize edge processing)
 
 	// Expect "png" or "jpeg" image type
 	imageData, imageType, err := image.Decode(img)
 	if err != nil {
-		return err
+		return "", err
 	}
 	// Finished with file. Reset file pointer
 	img.Seek(0, 0)
 	if imageType == "jpeg" {
 		err = jpeg.Encode(buf, imageData, nil)
 		if err != nil {
-			return err
+			return "", err
 		}
 	} else if imageType == "png" {
 		err = png.Encode(buf, imageData)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
-	return nil
+	return "image/" + imageType, nil
 }
 
 // Initialize performs protocol-specific initialization for the device
@@ -95,12 +94,14 @@ func (s *SimpleDriver) HandleReadCommands(deviceName string, protocols map[strin
 		} else if reqs[0].DeviceResourceName == "Image" {
 			// Show a binary/image representation of the switch's on/off value
 			buf := new(bytes.Buffer)
+			var mediaType string
 			if s.switchButton == true {
-				err = getImageBytes("./res/on.png", buf)
+				mediaType, err = getImageBytes("./res/on.png", buf)
 			} else {
-				err = getImageBytes("./res/off.jpg", buf)
+				mediaType, err = getImageBytes("./res/off.jpg", buf)
 			}
 			cvb, _ := dsModels.NewBinaryValue(reqs[0].DeviceResourceName, now, buf.Bytes())
+			cvb.MediaType = mediaType
 			res[0] = cvb
 		}
 	} else if len(reqs) == 3 {
@@ -192,3 +193,21 @@ func (s *SimpleDriver) RemoveDevice(deviceName string, protocols map[string]cont
 	s.lc.Debug(fmt.Sprintf("Device %s is removed", deviceName))
 	return nil
 }
+
+// Discover triggers protocol specific device discovery, which is a synchronous
+// operation which returns a list of new devices which may be added to the device
+// service based on the ProvisionWatchers of the device service.
+func (s *SimpleDriver) Discover() ([]dsModels.DiscoveredDevice, error) {
+	s.lc.Debug("SimpleDriver.Discover called")
+	devices := []dsModels.DiscoveredDevice{
+		{
+			Name: "Simple-Device02",
+			Protocols: map[string]contract.ProtocolProperties{
+				"other": {"Address": "simple02", "Port": "301"},
+			},
+			Description: "Example of Simple Device found by discovery",
+			Labels:      []string{"auto-discovery"},
+		},
+	}
+	return devices, nil
+}
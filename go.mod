@@ -2,6 +2,7 @@ module github.com/edgexfoundry/device-sdk-go
 
 require (
 	github.com/OneOfOne/xxhash v1.2.6
+	github.com/eclipse/paho.mqtt.golang v1.2.0
 	github.com/edgexfoundry/go-mod-core-contracts v0.1.36
 	github.com/edgexfoundry/go-mod-registry v0.1.0
 	github.com/google/uuid v1.1.0
@@ -0,0 +1,182 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package async processes the asynchronous readings pushed by the Driver.
+package async
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OneOfOne/xxhash"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+var (
+	current      *Pool
+	currentMutex sync.Mutex
+)
+
+// Pool processes the AsyncValues pushed by the Driver with a fixed number of
+// workers. The AsyncValues of a Device are always handled by the same worker,
+// so they're processed in the order they were pushed.
+type Pool struct {
+	ch      <-chan *dsModels.AsyncValues
+	handler func(*dsModels.AsyncValues)
+	queues  []chan *dsModels.AsyncValues
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	blocked   uint64
+	processed uint64
+}
+
+// NewPool creates a Pool reading from ch with the given number of workers,
+// each worker buffers up to bufferSize AsyncValues. When the buffer of a
+// worker is full the Pool stops reading from ch until the worker catches up,
+// so the Driver is blocked rather than its AsyncValues dropped.
+func NewPool(ch <-chan *dsModels.AsyncValues, workers int, bufferSize int, handler func(*dsModels.AsyncValues)) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	p := &Pool{ch: ch, handler: handler, stopCh: make(chan struct{})}
+	p.queues = make([]chan *dsModels.AsyncValues, workers)
+	for i := range p.queues {
+		p.queues[i] = make(chan *dsModels.AsyncValues, bufferSize)
+	}
+	return p
+}
+
+// Start starts the dispatcher and the workers of the Pool.
+func (p *Pool) Start() {
+	currentMutex.Lock()
+	current = p
+	currentMutex.Unlock()
+
+	p.wg.Add(len(p.queues))
+	for _, queue := range p.queues {
+		go p.work(queue)
+	}
+	go p.dispatch()
+}
+
+// Stop stops reading from the channel after the AsyncValues already in it are
+// dispatched, and waits up to timeout for the workers to process them. It
+// returns false if the workers didn't finish in time.
+func (p *Pool) Stop(timeout time.Duration) bool {
+	p.stopOnce.Do(func() { close(p.stopCh) })
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		common.LoggingClient.Warn(fmt.Sprintf("the asynchronous readings are not processed within %v, %d are left", timeout, p.queued()))
+		return false
+	}
+}
+
+func (p *Pool) dispatch() {
+	for {
+		select {
+		case acv := <-p.ch:
+			p.enqueue(acv)
+		case <-p.stopCh:
+			p.drain()
+			return
+		}
+	}
+}
+
+// drain dispatches the AsyncValues left in the channel, then closes the queues
+// of the workers so they exit once their queues are empty.
+func (p *Pool) drain() {
+	defer func() {
+		for _, queue := range p.queues {
+			close(queue)
+		}
+	}()
+
+	for {
+		select {
+		case acv := <-p.ch:
+			p.enqueue(acv)
+		default:
+			return
+		}
+	}
+}
+
+// enqueue hands the AsyncValues to the worker of their Device, it waits for
+// room in the buffer of the worker if it's full.
+func (p *Pool) enqueue(acv *dsModels.AsyncValues) {
+	if acv == nil {
+		return
+	}
+
+	queue := p.queues[xxhash.ChecksumString64(acv.DeviceName)%uint64(len(p.queues))]
+	select {
+	case queue <- acv:
+	default:
+		atomic.AddUint64(&p.blocked, 1)
+		common.LoggingClient.Debug(fmt.Sprintf("the asynchronous reading buffer is full, waiting to queue the readings of device %s", acv.DeviceName))
+		queue <- acv
+	}
+}
+
+func (p *Pool) work(queue <-chan *dsModels.AsyncValues) {
+	defer p.wg.Done()
+
+	for acv := range queue {
+		p.handler(acv)
+		atomic.AddUint64(&p.processed, 1)
+	}
+}
+
+func (p *Pool) queued() int {
+	n := 0
+	for _, queue := range p.queues {
+		n += len(queue)
+	}
+	return n
+}
+
+// Metrics returns the backpressure metrics of the Pool.
+func (p *Pool) Metrics() common.AsyncMetrics {
+	return common.AsyncMetrics{
+		ChannelDepth:    uint64(len(p.ch)),
+		ChannelCapacity: uint64(cap(p.ch)),
+		Queued:          uint64(p.queued()),
+		Blocked:         atomic.LoadUint64(&p.blocked),
+		Processed:       atomic.LoadUint64(&p.processed),
+	}
+}
+
+// Metrics returns the metrics of the running Pool, they are all zero if the
+// asynchronous readings are disabled.
+func Metrics() common.AsyncMetrics {
+	currentMutex.Lock()
+	defer currentMutex.Unlock()
+
+	if current == nil {
+		return common.AsyncMetrics{}
+	}
+	return current.Metrics()
+}
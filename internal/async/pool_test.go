@@ -0,0 +1,135 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package async
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+)
+
+func init() {
+	common.LoggingClient = logger.NewMockClient()
+}
+
+func newAsyncValues(device string, origin int64) *dsModels.AsyncValues {
+	cv := &dsModels.CommandValue{DeviceResourceName: "resource", Origin: origin}
+	return &dsModels.AsyncValues{DeviceName: device, CommandValues: []*dsModels.CommandValue{cv}}
+}
+
+func TestPoolOrderPerDevice(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 16)
+	var mutex sync.Mutex
+	received := make(map[string][]int64)
+	p := NewPool(ch, 4, 64, func(acv *dsModels.AsyncValues) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		received[acv.DeviceName] = append(received[acv.DeviceName], acv.CommandValues[0].Origin)
+	})
+	p.Start()
+
+	devices := []string{"device1", "device2", "device3", "device4", "device5"}
+	for i := int64(0); i < 50; i++ {
+		for _, device := range devices {
+			ch <- newAsyncValues(device, i)
+		}
+	}
+	if !p.Stop(time.Second) {
+		t.Fatal("the pool is not stopped in time")
+	}
+
+	for _, device := range devices {
+		values := received[device]
+		if len(values) != 50 {
+			t.Fatalf("expected 50 readings of %s, got %d", device, len(values))
+		}
+		for i, v := range values {
+			if v != int64(i) {
+				t.Fatalf("the readings of %s are out of order: %v", device, values)
+			}
+		}
+	}
+	if m := p.Metrics(); m.Processed != 250 || m.ChannelCapacity != 16 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+}
+
+func TestPoolBackpressureAndStopDeadline(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 1)
+	release := make(chan bool)
+	started := make(chan bool, 1)
+	var mutex sync.Mutex
+	var received []int64
+	p := NewPool(ch, 1, 1, func(acv *dsModels.AsyncValues) {
+		select {
+		case started <- true:
+		default:
+		}
+		<-release
+		mutex.Lock()
+		received = append(received, acv.CommandValues[0].Origin)
+		mutex.Unlock()
+	})
+	p.Start()
+
+	// the first value blocks the worker, the second fills its buffer, the
+	// third waits in the dispatcher and the fourth in the channel
+	ch <- newAsyncValues("device1", 1)
+	<-started
+	for i := 2; i <= 4; i++ {
+		ch <- newAsyncValues("device1", int64(i))
+	}
+	for i := 0; i < 100 && p.Metrics().Blocked < 1; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if m := p.Metrics(); m.Blocked != 1 || m.Queued != 1 || m.ChannelDepth != 1 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+
+	// the Driver is blocked rather than its readings dropped
+	pushed := make(chan bool)
+	go func() {
+		ch <- newAsyncValues("device1", 5)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("the push is supposed to block while the buffers are full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// the push goes through once the worker catches up
+	release <- true
+	release <- true
+	<-pushed
+
+	if p.Stop(20 * time.Millisecond) {
+		t.Error("the pool is not supposed to stop while the worker is blocked")
+	}
+	close(release)
+	if !p.Stop(time.Second) {
+		t.Error("the pool is supposed to stop once the worker is released")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(received) != 5 {
+		t.Fatalf("expected the 5 readings to be processed, got %v", received)
+	}
+	for i, v := range received {
+		if v != int64(i+1) {
+			t.Fatalf("the readings are out of order: %v", received)
+		}
+	}
+	if m := Metrics(); m.Processed != 5 {
+		t.Errorf("expected 5 processed values, got %+v", m)
+	}
+}
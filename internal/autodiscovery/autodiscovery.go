@@ -0,0 +1,80 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autodiscovery
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+var (
+	stopCh    chan struct{}
+	stopMutex sync.Mutex
+)
+
+// Run starts the periodic device discovery in the background if it's enabled in
+// the [Device.Discovery] configuration and the Driver supports device discovery.
+func Run() {
+	config := common.CurrentConfig.Device.Discovery
+	if !config.Enabled {
+		common.LoggingClient.Info("periodic device discovery is disabled")
+		return
+	}
+
+	if common.Discovery == nil {
+		common.LoggingClient.Info("periodic device discovery is enabled, but the Driver doesn't support device discovery")
+		return
+	}
+
+	duration, err := time.ParseDuration(config.Interval)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Discovery Interval %s cannot be parsed, %v", config.Interval, err))
+		return
+	} else if duration <= 0 {
+		common.LoggingClient.Error(fmt.Sprintf("Discovery Interval %s should be greater than zero", config.Interval))
+		return
+	}
+
+	stopMutex.Lock()
+	defer stopMutex.Unlock()
+	if stopCh != nil {
+		common.LoggingClient.Debug("periodic device discovery is already running")
+		return
+	}
+	stopCh = make(chan struct{})
+
+	common.LoggingClient.Info(fmt.Sprintf("starting periodic device discovery with interval %v", duration))
+	go runPeriodically(duration, stopCh)
+}
+
+func runPeriodically(duration time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			DiscoveryWrapper(common.Discovery)
+		}
+	}
+}
+
+// Stop stops the periodic device discovery.
+func Stop() {
+	stopMutex.Lock()
+	defer stopMutex.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+		stopCh = nil
+	}
+}
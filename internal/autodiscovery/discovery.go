@@ -0,0 +1,202 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autodiscovery
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/google/uuid"
+)
+
+var locker = struct {
+	mux  sync.Mutex
+	busy bool
+}{}
+
+// DiscoveryWrapper triggers the protocol specific device discovery and adds the
+// found devices which pass the ProvisionWatchers to Core Metadata. The discovery
+// is skipped if another discovery run is already in progress.
+func DiscoveryWrapper(discovery dsModels.ProtocolDiscovery) {
+	if !acquireLock() {
+		common.LoggingClient.Info("another device discovery process is currently running, skipping")
+		return
+	}
+	defer releaseLock()
+
+	discover(discovery)
+}
+
+// StartDiscovery triggers the device discovery in the background. It returns false
+// without triggering the discovery if another discovery run is already in progress.
+func StartDiscovery(discovery dsModels.ProtocolDiscovery) bool {
+	if !acquireLock() {
+		return false
+	}
+
+	go func() {
+		defer releaseLock()
+		discover(discovery)
+	}()
+	return true
+}
+
+func acquireLock() bool {
+	locker.mux.Lock()
+	defer locker.mux.Unlock()
+
+	if locker.busy {
+		return false
+	}
+	locker.busy = true
+	return true
+}
+
+func releaseLock() {
+	locker.mux.Lock()
+	locker.busy = false
+	locker.mux.Unlock()
+}
+
+func discover(discovery dsModels.ProtocolDiscovery) {
+	if discovery == nil {
+		common.LoggingClient.Error("protocol discovery is not supported by the Driver")
+		return
+	}
+
+	common.LoggingClient.Debug("protocol discovery triggered")
+	devices, err := discovery.Discover()
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("protocol discovery failed: %v", err))
+		return
+	}
+
+	common.LoggingClient.Debug(fmt.Sprintf("protocol discovery found %d device(s)", len(devices)))
+	filterAndAddDevices(devices)
+}
+
+func filterAndAddDevices(devices []dsModels.DiscoveredDevice) {
+	pws := cache.ProvisionWatchers().All()
+	added := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		if _, ok := cache.Devices().ForName(d.Name); ok || added[d.Name] {
+			common.LoggingClient.Debug(fmt.Sprintf("Discovered Device %s already exists, skipping", d.Name))
+			continue
+		}
+
+		for _, pw := range pws {
+			if pw.AdminState == contract.Locked {
+				common.LoggingClient.Debug(fmt.Sprintf("ProvisionWatcher %s is locked, skipping", pw.Name))
+				continue
+			}
+
+			if whitelistPass(d, pw) && blacklistPass(d, pw) {
+				common.LoggingClient.Info(fmt.Sprintf("Discovered Device %s matches ProvisionWatcher %s", d.Name, pw.Name))
+				err := createDiscoveredDevice(d, pw)
+				if err != nil {
+					common.LoggingClient.Error(fmt.Sprintf("creating discovered Device %s failed: %v", d.Name, err))
+				} else {
+					added[d.Name] = true
+				}
+				break
+			}
+		}
+	}
+}
+
+// whitelistPass checks whether all the Identifiers of the ProvisionWatcher are
+// matched by the protocol properties of the discovered device. The value of each
+// identifier is a regular expression. A ProvisionWatcher without Identifiers
+// matches no device.
+func whitelistPass(d dsModels.DiscoveredDevice, pw contract.ProvisionWatcher) bool {
+	if len(pw.Identifiers) == 0 {
+		common.LoggingClient.Debug(fmt.Sprintf("ProvisionWatcher %s has no identifiers, it matches no device", pw.Name))
+		return false
+	}
+	// ignore the name of the device protocol properties
+	for _, protocol := range d.Protocols {
+		matchedCount := 0
+		for name, regex := range pw.Identifiers {
+			if value, ok := protocol[name]; ok {
+				matched, err := regexp.MatchString(regex, value)
+				if err != nil {
+					common.LoggingClient.Error(fmt.Sprintf("ProvisionWatcher %s identifier %s has an invalid regular expression %s: %v", pw.Name, name, regex, err))
+					break
+				}
+				if !matched {
+					common.LoggingClient.Debug(fmt.Sprintf("Device %s's %s value %s did not match ProvisionWatcher %s identifier: %s", d.Name, name, value, pw.Name, regex))
+					break
+				}
+				matchedCount++
+			}
+		}
+		// the device matches only when all the identifiers are matched
+		if matchedCount == len(pw.Identifiers) {
+			return true
+		}
+	}
+	return false
+}
+
+// blacklistPass checks that the discovered device matches none of the
+// BlockingIdentifiers of the ProvisionWatcher.
+func blacklistPass(d dsModels.DiscoveredDevice, pw contract.ProvisionWatcher) bool {
+	for name, blacklist := range pw.BlockingIdentifiers {
+		// ignore the name of the device protocol properties
+		for _, protocol := range d.Protocols {
+			if value, ok := protocol[name]; ok {
+				for _, v := range blacklist {
+					if value == v {
+						common.LoggingClient.Debug(fmt.Sprintf("Device %s's %s value %s is blocked by ProvisionWatcher %s", d.Name, name, value, pw.Name))
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+func createDiscoveredDevice(d dsModels.DiscoveredDevice, pw contract.ProvisionWatcher) error {
+	adminState := pw.AdminState
+	if adminState == "" {
+		adminState = contract.Unlocked
+	}
+
+	millis := time.Now().UnixNano() / int64(time.Millisecond)
+	device := &contract.Device{
+		Name:           d.Name,
+		Profile:        pw.Profile,
+		Protocols:      d.Protocols,
+		Labels:         d.Labels,
+		Service:        common.CurrentDeviceService,
+		AdminState:     adminState,
+		OperatingState: contract.Enabled,
+	}
+	device.Origin = millis
+	device.Description = d.Description
+	common.LoggingClient.Debug(fmt.Sprintf("Adding discovered Device: %v", device))
+	ctx := context.WithValue(context.Background(), common.CorrelationHeader, uuid.New().String())
+	id, err := common.DeviceClient.Add(device, ctx)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Add discovered Device failed %s, error: %v", device.Name, err))
+		return err
+	}
+	if err = common.VerifyIdFormat(id, "Device"); err != nil {
+		return err
+	}
+	device.Id = id
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autodiscovery
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+type discoveryMock struct {
+	started chan bool
+	release chan bool
+}
+
+func (d discoveryMock) Discover() ([]dsModels.DiscoveredDevice, error) {
+	d.started <- true
+	<-d.release
+	return nil, fmt.Errorf("discovery failed")
+}
+
+func init() {
+	common.LoggingClient = logger.NewMockClient()
+	common.ValueDescriptorClient = &mock.ValueDescriptorMock{}
+	common.DeviceClient = &mock.DeviceClientMock{}
+	common.ProvisionWatcherClient = &mock.ProvisionWatcherClientMock{}
+	cache.InitCache()
+}
+
+func TestStartDiscoveryInProgress(t *testing.T) {
+	discovery := discoveryMock{started: make(chan bool), release: make(chan bool)}
+
+	if !StartDiscovery(discovery) {
+		t.Fatal("the first discovery run is supposed to be started")
+	}
+	<-discovery.started
+
+	if StartDiscovery(discovery) {
+		t.Error("the discovery run is not supposed to be started while another run is in progress")
+	}
+
+	discovery.release <- true
+	// wait until the lock is released by the first run
+	for !acquireLock() {
+	}
+	releaseLock()
+
+	if !StartDiscovery(discovery) {
+		t.Error("the discovery run is supposed to be started after the previous run completed")
+	}
+	<-discovery.started
+	discovery.release <- true
+}
+
+func TestFilterAndAddDevicesExisting(t *testing.T) {
+	devices := []dsModels.DiscoveredDevice{
+		{
+			Name: mock.ValidDeviceRandomFloatGenerator.Name,
+			Protocols: map[string]contract.ProtocolProperties{
+				"other": {"float-key": "float-value"},
+			},
+		},
+	}
+
+	// the mocked DeviceClient panics on Add, so it must not be called for an existing Device
+	filterAndAddDevices(devices)
+}
+
+func TestWhitelistPass(t *testing.T) {
+	d := dsModels.DiscoveredDevice{
+		Name: "Discovered-Device",
+		Protocols: map[string]contract.ProtocolProperties{
+			"other": {"Address": "simple01", "Port": "300"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		identifiers map[string]string
+		expected    bool
+	}{
+		{"exact match", map[string]string{"Address": "simple01"}, true},
+		{"regex match", map[string]string{"Address": "simple[0-9]+", "Port": "3.."}, true},
+		{"partial match", map[string]string{"Address": "simple01", "Port": "400"}, false},
+		{"no match", map[string]string{"Address": "modbus01"}, false},
+		{"missing identifier", map[string]string{"MAC": "00-05-1B-A1-99-99"}, false},
+		{"invalid regex", map[string]string{"Address": "simple[0-9"}, false},
+		{"no identifiers", nil, false},
+		{"empty identifiers", map[string]string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := contract.ProvisionWatcher{Name: "test-watcher", Identifiers: tt.identifiers}
+			if result := whitelistPass(d, pw); result != tt.expected {
+				t.Errorf("whitelistPass returned %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlacklistPass(t *testing.T) {
+	d := dsModels.DiscoveredDevice{
+		Name: "Discovered-Device",
+		Protocols: map[string]contract.ProtocolProperties{
+			"other": {"Address": "simple01", "Port": "300"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		blocking map[string][]string
+		expected bool
+	}{
+		{"no blocking identifiers", nil, true},
+		{"not blocked", map[string][]string{"Port": {"399", "400"}}, true},
+		{"blocked", map[string][]string{"Port": {"300", "400"}}, false},
+		{"unknown property", map[string][]string{"MAC": {"00-05-1B-A1-99-99"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := contract.ProvisionWatcher{Name: "test-watcher", BlockingIdentifiers: tt.blocking}
+			if result := blacklistPass(d, pw); result != tt.expected {
+				t.Errorf("blacklistPass returned %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
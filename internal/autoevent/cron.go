@@ -0,0 +1,287 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search of the next tick, the expressions which
+// match no time within it, such as "0 0 30 2 *", are rejected.
+const cronSearchYears = 5
+
+// cronMaxShift is longer than any daylight-saving change, a wall-clock time
+// repeated by a change is at most that much earlier than the one before it.
+const cronMaxShift = 3 * time.Hour
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var dayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// cronField is the set of the values matched by a field of a cron expression.
+type cronField uint64
+
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// cronSchedule runs on the wall-clock times of a five fields cron expression:
+// minute, hour, day of month, month and day of week, in the time zone given by
+// a leading "TZ=" or "CRON_TZ=", the local time zone otherwise. As in Vixie
+// cron, a wall-clock time repeated by a daylight-saving change runs on both
+// occurrences if the hour field is "*" or a step, such as "0 * * * *" or
+// "0 */2 * * *", and once on its first occurrence otherwise. The times skipped
+// by a daylight-saving change run at the change.
+type cronSchedule struct {
+	minute, hour, dom, month, dow cronField
+	// the day matches either the day of month or the day of week when both
+	// are restricted, as in cron
+	domStar, dowStar bool
+	// hourWildcard is whether the hour field is "*" or a step
+	hourWildcard bool
+	location     *time.Location
+}
+
+func parseCron(expression string) (*cronSchedule, error) {
+	spec := strings.TrimSpace(expression)
+	c := &cronSchedule{location: time.Local}
+
+	for _, prefix := range []string{"TZ=", "CRON_TZ="} {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+		fields := strings.SplitN(spec[len(prefix):], " ", 2)
+		location, err := time.LoadLocation(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone in cron expression %s: %v", expression, err)
+		}
+		c.location = location
+		spec = ""
+		if len(fields) > 1 {
+			spec = strings.TrimSpace(fields[1])
+		}
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		descriptor, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown descriptor %s in cron expression %s", spec, expression)
+		}
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %s should have 5 fields: minute, hour, day of month, month and day of week", expression)
+	}
+
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute in cron expression %s: %v", expression, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour in cron expression %s: %v", expression, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month in cron expression %s: %v", expression, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month in cron expression %s: %v", expression, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid day of week in cron expression %s: %v", expression, err)
+	}
+	if c.dow.has(7) {
+		// 7 is Sunday as well as 0
+		c.dow |= 1
+	}
+	c.domStar = strings.HasPrefix(fields[2], "*")
+	c.dowStar = strings.HasPrefix(fields[4], "*")
+	c.hourWildcard = strings.HasPrefix(fields[1], "*") || strings.Contains(fields[1], "/")
+
+	now := time.Now()
+	if c.next(now, now).IsZero() {
+		return nil, fmt.Errorf("cron expression %s matches no time in the next %d years", expression, cronSearchYears)
+	}
+	return c, nil
+}
+
+// parseCronField parses a comma separated list of "*", values, ranges such as
+// "1-5", each optionally followed by a step such as "*/15" or "0-30/10".
+func parseCronField(field string, min int, max int, names map[string]int) (cronField, error) {
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s", part)
+			}
+			rangePart = part[:i]
+		}
+
+		low, high := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if low, err = parseCronValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(bounds[1], names); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if low, err = parseCronValue(rangePart, names); err != nil {
+				return 0, err
+			}
+			if step == 1 {
+				high = low
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%s is out of the range %d-%d", part, min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			f |= 1 << uint(v)
+		}
+	}
+	return f, nil
+}
+
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s", s)
+	}
+	return v, nil
+}
+
+// next returns the first time matching the expression after both the
+// previous tick and now, the zero time if there is none within
+// cronSearchYears.
+func (c *cronSchedule) next(tick time.Time, now time.Time) time.Time {
+	after := now
+	if tick.After(now) {
+		after = tick
+	}
+
+	// the wall-clock time is searched in UTC, which has no daylight-saving
+	// changes, then converted to the location. The search starts before the
+	// wall-clock time of after, whose occurrence repeated by a daylight-saving
+	// change can be after it, and ends once no later wall-clock time can have
+	// an earlier instant than the one found.
+	a := after.In(c.location)
+	wall := time.Date(a.Year(), a.Month(), a.Day(), a.Hour(), a.Minute(), 0, 0, time.UTC).Add(-cronMaxShift)
+	limit := wall.AddDate(cronSearchYears, 0, 0)
+	var found, foundWall time.Time
+	for wall.Before(limit) {
+		if !found.IsZero() && wall.After(foundWall.Add(cronMaxShift)) {
+			return found
+		}
+		switch {
+		case !c.month.has(int(wall.Month())):
+			wall = time.Date(wall.Year(), wall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(wall):
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !c.hour.has(wall.Hour()):
+			wall = wall.Truncate(time.Hour).Add(time.Hour)
+		case !c.minute.has(wall.Minute()):
+			wall = wall.Add(time.Minute)
+		default:
+			instants := c.instants(wall)
+			if !c.hourWildcard {
+				instants = instants[:1]
+			}
+			for _, t := range instants {
+				if t.After(after) && (found.IsZero() || t.Before(found)) {
+					found, foundWall = t, wall
+				}
+			}
+			wall = wall.Add(time.Minute)
+		}
+	}
+	return found
+}
+
+func (c *cronSchedule) dayMatches(wall time.Time) bool {
+	dom := c.dom.has(wall.Day())
+	dow := c.dow.has(int(wall.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// instants returns the instants the clock of the location shows the
+// wall-clock time at in chronological order, two if a daylight-saving change
+// repeats the wall-clock time, or the instant of the daylight-saving change if
+// the change skips the wall-clock time.
+func (c *cronSchedule) instants(wall time.Time) []time.Time {
+	var instants []time.Time
+	for _, probe := range []time.Time{wall.Add(-24 * time.Hour), wall.Add(24 * time.Hour)} {
+		_, offset := probe.In(c.location).Zone()
+		t := wall.Add(-time.Duration(offset) * time.Second).In(c.location)
+		if _, o := t.Zone(); o == offset {
+			instants = append(instants, t)
+		}
+	}
+	if len(instants) > 0 {
+		sort.Slice(instants, func(i, j int) bool { return instants[i].Before(instants[j]) })
+		if len(instants) == 2 && instants[0].Equal(instants[1]) {
+			instants = instants[:1]
+		}
+		return instants
+	}
+
+	// the wall-clock time is in the gap of a daylight-saving change, find the
+	// first second whose wall-clock time is past it
+	_, before := wall.Add(-24 * time.Hour).In(c.location).Zone()
+	_, after := wall.Add(24 * time.Hour).In(c.location).Zone()
+	low := wall.Unix() - int64(after)
+	high := wall.Unix() - int64(before)
+	for high-low > 1 {
+		mid := low + (high-low)/2
+		if wallClock(time.Unix(mid, 0).In(c.location)).Before(wall) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return []time.Time{time.Unix(high, 0).In(c.location)}
+}
+
+// wallClock returns the wall-clock time of t as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
@@ -0,0 +1,199 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("the time zone %s isn't available: %v", name, err)
+	}
+	return location
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		testName    string
+		frequency   string
+		cron        bool
+		expectedErr bool
+	}{
+		{"Duration", "10s", false, false},
+		{"Cron", "cron:0 * * * *", true, false},
+		{"Descriptor", "@daily", true, false},
+		{"PrefixedDescriptor", "cron:@hourly", true, false},
+		{"TimeZone", "cron:TZ=UTC 0 2 * * *", true, false},
+		{"Names", "cron:0 8 * JAN-MAR MON-FRI", true, false},
+		{"Steps", "cron:*/15 0-12/2 1,15 * 7", true, false},
+		{"ZeroDuration", "0s", false, true},
+		{"MissingField", "cron:0 * * *", false, true},
+		{"OutOfRange", "cron:60 * * * *", false, true},
+		{"InvalidStep", "cron:*/0 * * * *", false, true},
+		{"InvalidRange", "cron:0 5-1 * * *", false, true},
+		{"UnknownDescriptor", "@often", false, true},
+		{"UnknownTimeZone", "cron:TZ=Nowhere/City 0 * * * *", false, true},
+		{"NeverMatches", "cron:0 0 30 2 *", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			s, err := parseSchedule(tt.frequency)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				_, isCron := s.(*cronSchedule)
+				assert.Equal(t, tt.cron, isCron)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	tests := []struct {
+		testName   string
+		expression string
+		now        time.Time
+		expected   time.Time
+	}{
+		{"Hourly", "TZ=UTC 0 * * * *",
+			time.Date(2020, 5, 4, 10, 20, 30, 0, time.UTC), time.Date(2020, 5, 4, 11, 0, 0, 0, time.UTC)},
+		{"OnTheTick", "TZ=UTC 0 * * * *",
+			time.Date(2020, 5, 4, 11, 0, 0, 0, time.UTC), time.Date(2020, 5, 4, 12, 0, 0, 0, time.UTC)},
+		{"Daily", "TZ=UTC 0 2 * * *",
+			time.Date(2020, 5, 4, 2, 0, 1, 0, time.UTC), time.Date(2020, 5, 5, 2, 0, 0, 0, time.UTC)},
+		{"Descriptor", "TZ=UTC @monthly",
+			time.Date(2020, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"LeapDay", "TZ=UTC 0 0 29 2 *",
+			time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// 2020-05-04 is a Monday, either the 10th or a Friday matches
+		{"DayOfMonthOrWeek", "TZ=UTC 0 0 10 * FRI",
+			time.Date(2020, 5, 4, 0, 0, 0, 0, time.UTC), time.Date(2020, 5, 8, 0, 0, 0, 0, time.UTC)},
+		{"TimeZone", "TZ=Europe/Berlin 0 2 * * *",
+			time.Date(2020, 5, 4, 12, 0, 0, 0, time.UTC), time.Date(2020, 5, 5, 2, 0, 0, 0, berlin)},
+		// the clocks go from 02:00 CET to 03:00 CEST on 2020-03-29
+		{"SpringGap", "TZ=Europe/Berlin 30 2 * * *",
+			time.Date(2020, 3, 28, 12, 0, 0, 0, berlin), time.Date(2020, 3, 29, 1, 0, 0, 0, time.UTC)},
+		{"AfterSpringGap", "TZ=Europe/Berlin 30 2 * * *",
+			time.Date(2020, 3, 29, 12, 0, 0, 0, berlin), time.Date(2020, 3, 30, 2, 30, 0, 0, berlin)},
+		// the clocks go from 03:00 CEST back to 02:00 CET on 2020-10-25
+		{"FallBack", "TZ=Europe/Berlin 30 2 * * *",
+			time.Date(2020, 10, 24, 12, 0, 0, 0, berlin), time.Date(2020, 10, 25, 0, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c, err := parseCron(tt.expression)
+			if !assert.NoError(t, err) {
+				return
+			}
+			next := c.next(tt.now, tt.now)
+			assert.True(t, tt.expected.Equal(next), "expected %v, got %v", tt.expected, next)
+		})
+	}
+}
+
+func TestCronScheduleFallBackRunsOnce(t *testing.T) {
+	c, err := parseCron("TZ=Europe/Berlin 30 2 * * *")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the first 02:30 is 00:30 UTC, the repeated 02:30 at 01:30 UTC is skipped
+	tick := time.Date(2020, 10, 25, 0, 30, 0, 0, time.UTC)
+	next := c.next(tick, tick)
+	assert.True(t, time.Date(2020, 10, 26, 1, 30, 0, 0, time.UTC).Equal(next), "got %v", next)
+}
+
+// TestCronScheduleFallBackWildcardHour checks that the expressions whose hour
+// field is "*" or a step run on both occurrences of the repeated hour.
+func TestCronScheduleFallBackWildcardHour(t *testing.T) {
+	// the clocks go from 02:00 EDT back to 01:00 EST on 2026-11-01, 01:00 EDT
+	// is 05:00 UTC and 01:00 EST is 06:00 UTC
+	tests := []struct {
+		testName   string
+		expression string
+		start      time.Time
+		expected   []time.Time
+	}{
+		{"Hourly", "TZ=America/New_York 0 * * * *", time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC),
+			[]time.Time{
+				time.Date(2026, 11, 1, 5, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC),
+			}},
+		{"EveryHalfHour", "TZ=America/New_York */30 * * * *", time.Date(2026, 11, 1, 4, 30, 0, 0, time.UTC),
+			[]time.Time{
+				time.Date(2026, 11, 1, 5, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC),
+			}},
+		{"HourStep", "TZ=America/New_York 30 1-3/1 * * *", time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC),
+			[]time.Time{
+				time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 7, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 8, 30, 0, 0, time.UTC),
+			}},
+		// a fixed hour runs once, on the first occurrence
+		{"FixedHours", "TZ=America/New_York 30 1,2 * * *", time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC),
+			[]time.Time{
+				time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 7, 30, 0, 0, time.UTC),
+				time.Date(2026, 11, 2, 6, 30, 0, 0, time.UTC),
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c, err := parseCron(tt.expression)
+			if !assert.NoError(t, err) {
+				return
+			}
+			tick := tt.start
+			for i, expected := range tt.expected {
+				tick = c.next(tick, tick)
+				assert.True(t, expected.Equal(tick), "tick %d: expected %v, got %v", i, expected, tick.UTC())
+			}
+		})
+	}
+}
+
+// TestCronScheduleSpringForwardWildcardHour checks that the skipped hour runs
+// once at the change for the expressions whose hour field is "*".
+func TestCronScheduleSpringForwardWildcardHour(t *testing.T) {
+	c, err := parseCron("TZ=America/New_York 0 * * * *")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the clocks go from 02:00 EST to 03:00 EDT on 2026-03-08, at 07:00 UTC
+	tick := time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)
+	for _, expected := range []time.Time{
+		time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 8, 8, 0, 0, 0, time.UTC),
+	} {
+		tick = c.next(tick, tick)
+		assert.True(t, expected.Equal(tick), "expected %v, got %v", expected, tick.UTC())
+	}
+}
+
+func TestIntervalScheduleNext(t *testing.T) {
+	s := intervalSchedule(10 * time.Second)
+	start := time.Date(2020, 5, 4, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, start.Add(10*time.Second), s.next(start, start))
+	// the missed ticks are skipped
+	assert.Equal(t, start.Add(40*time.Second), s.next(start, start.Add(35*time.Second)))
+}
@@ -0,0 +1,141 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// DeviceResource attributes of the deadbands of the OnChange AutoEvents. A
+// numeric reading which changed by no more than a deadband since the last
+// reported value isn't a change, when both are set the change must exceed
+// both of them.
+const (
+	// DeadbandAttribute is the absolute deadband, e.g. "0.5".
+	DeadbandAttribute = "deadband"
+	// DeadbandPercentAttribute is the deadband as a percentage of the last
+	// reported value, e.g. "2" for 2%.
+	DeadbandPercentAttribute = "deadbandPercent"
+)
+
+// deadband is the deadband of a numeric DeviceResource.
+type deadband struct {
+	absolute      float64
+	percent       float64
+	valueType     dsModels.ValueType
+	floatEncoding string
+}
+
+// newDeadband returns the deadband of the DeviceResource, nil if it has none.
+func newDeadband(dr contract.DeviceResource) (*deadband, error) {
+	absolute, hasAbsolute := dr.Attributes[DeadbandAttribute]
+	percent, hasPercent := dr.Attributes[DeadbandPercentAttribute]
+	if !hasAbsolute && !hasPercent {
+		return nil, nil
+	}
+
+	d := &deadband{
+		valueType:     dsModels.ParseValueType(dr.Properties.Value.Type),
+		floatEncoding: dr.Properties.Value.FloatEncoding,
+	}
+	switch d.valueType {
+	case dsModels.Uint8, dsModels.Uint16, dsModels.Uint32, dsModels.Uint64,
+		dsModels.Int8, dsModels.Int16, dsModels.Int32, dsModels.Int64, dsModels.Float32, dsModels.Float64:
+	default:
+		return nil, fmt.Errorf("the deadband of DeviceResource %s requires a numeric type, not %s", dr.Name, dr.Properties.Value.Type)
+	}
+
+	var err error
+	if hasAbsolute {
+		if d.absolute, err = parseDeadband(absolute); err != nil {
+			return nil, fmt.Errorf("invalid %s of DeviceResource %s: %v", DeadbandAttribute, dr.Name, err)
+		}
+	}
+	if hasPercent {
+		if d.percent, err = parseDeadband(percent); err != nil {
+			return nil, fmt.Errorf("invalid %s of DeviceResource %s: %v", DeadbandPercentAttribute, dr.Name, err)
+		}
+	}
+	return d, nil
+}
+
+func parseDeadband(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	} else if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%s should be a finite number greater than or equal to zero", s)
+	}
+	return v, nil
+}
+
+// value returns the numeric value of the reading, false if it isn't a number.
+func (d *deadband) value(r contract.Reading) (float64, bool) {
+	var v float64
+	var err error
+	if (d.valueType == dsModels.Float32 || d.valueType == dsModels.Float64) && d.floatEncoding != contract.ENotation {
+		// the floats are base64 encoded unless the DeviceResource says otherwise
+		var b []byte
+		b, err = base64.StdEncoding.DecodeString(r.Value)
+		switch {
+		case err != nil:
+		case d.valueType == dsModels.Float32 && len(b) == 4:
+			v = float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		case d.valueType == dsModels.Float64 && len(b) == 8:
+			v = math.Float64frombits(binary.BigEndian.Uint64(b))
+		default:
+			err = fmt.Errorf("%d bytes can't be a %v", len(b), d.valueType)
+		}
+	} else {
+		v, err = strconv.ParseFloat(r.Value, 64)
+	}
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// exceeded returns whether the change from the last reported value exceeds
+// the deadband.
+func (d *deadband) exceeded(last float64, v float64) bool {
+	change := math.Abs(v - last)
+	return change > d.absolute && change > d.percent/100*math.Abs(last)
+}
+
+// resourceDeadbands returns the deadbands of the DeviceResources of the
+// readings by reading name, the DeviceResources without a valid deadband are
+// left out.
+func resourceDeadbands(deviceName string, readings []contract.Reading) map[string]*deadband {
+	device, ok := cache.Devices().ForName(deviceName)
+	if !ok {
+		return nil
+	}
+
+	deadbands := make(map[string]*deadband)
+	for _, r := range readings {
+		dr, ok := cache.Profiles().DeviceResource(device.Profile.Name, r.Name)
+		if !ok {
+			continue
+		}
+		d, err := newDeadband(dr)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("AutoEvent - %v, any change of %s is reported", err, r.Name))
+		} else if d != nil {
+			deadbands[r.Name] = d
+		}
+	}
+	return deadbands
+}
@@ -0,0 +1,174 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"testing"
+	"time"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDeadbandResource(valueType string, floatEncoding string, attributes map[string]string) contract.DeviceResource {
+	return contract.DeviceResource{
+		Name:       "Temperature",
+		Properties: contract.ProfileProperty{Value: contract.PropertyValue{Type: valueType, FloatEncoding: floatEncoding}},
+		Attributes: attributes,
+	}
+}
+
+func TestNewDeadband(t *testing.T) {
+	tests := []struct {
+		testName    string
+		dr          contract.DeviceResource
+		expected    *deadband
+		expectedErr bool
+	}{
+		{"NoDeadband", newDeadbandResource("Int32", "", nil), nil, false},
+		{"Absolute", newDeadbandResource("Int32", "", map[string]string{DeadbandAttribute: "2"}),
+			&deadband{absolute: 2, valueType: dsModels.Int32}, false},
+		{"Both", newDeadbandResource("Float64", contract.ENotation, map[string]string{DeadbandAttribute: "0.5", DeadbandPercentAttribute: "1"}),
+			&deadband{absolute: 0.5, percent: 1, valueType: dsModels.Float64, floatEncoding: contract.ENotation}, false},
+		{"NotNumeric", newDeadbandResource("String", "", map[string]string{DeadbandAttribute: "1"}), nil, true},
+		{"InvalidAbsolute", newDeadbandResource("Int32", "", map[string]string{DeadbandAttribute: "one"}), nil, true},
+		{"NegativePercent", newDeadbandResource("Int32", "", map[string]string{DeadbandPercentAttribute: "-1"}), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			d, err := newDeadband(tt.dr)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestDeadbandValue(t *testing.T) {
+	float32Value, _ := dsModels.NewFloat32Value("Temperature", 0, 21.5)
+	float64Value, _ := dsModels.NewFloat64Value("Temperature", 0, -3.25)
+
+	tests := []struct {
+		testName string
+		d        *deadband
+		value    string
+		expected float64
+		isNumber bool
+	}{
+		{"Integer", &deadband{valueType: dsModels.Int16}, "-12", -12, true},
+		{"Base64Float32", &deadband{valueType: dsModels.Float32}, float32Value.ValueToString(), 21.5, true},
+		{"Base64Float64", &deadband{valueType: dsModels.Float64, floatEncoding: contract.Base64Encoding}, float64Value.ValueToString(), -3.25, true},
+		{"ENotation", &deadband{valueType: dsModels.Float64, floatEncoding: contract.ENotation}, "2.150000e+01", 21.5, true},
+		{"NotANumber", &deadband{valueType: dsModels.Int16}, "high", 0, false},
+		{"InvalidBase64", &deadband{valueType: dsModels.Float32}, "2.5", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			v, isNumber := tt.d.value(contract.Reading{Name: "Temperature", Value: tt.value})
+			assert.Equal(t, tt.isNumber, isNumber)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestFilterEventDeadband(t *testing.T) {
+	tests := []struct {
+		testName string
+		d        *deadband
+		values   []string
+		sent     []bool
+	}{
+		// the changes are compared with the last reported value, so small
+		// changes add up to a reported change
+		{"Absolute", &deadband{absolute: 0.5, valueType: dsModels.Float64, floatEncoding: contract.ENotation},
+			[]string{"20.00", "20.01", "20.30", "20.51", "20.10", "20.00"}, []bool{true, false, false, true, false, true}},
+		{"Percent", &deadband{percent: 10, valueType: dsModels.Int32},
+			[]string{"100", "109", "91", "111", "100", "99"}, []bool{true, false, false, true, false, true}},
+		// both deadbands must be exceeded, the absolute one prevails near zero
+		{"Both", &deadband{absolute: 2, percent: 10, valueType: dsModels.Int32},
+			[]string{"0", "1", "3", "100", "109", "111"}, []bool{true, false, true, true, false, true}},
+		{"NotANumber", &deadband{absolute: 5, valueType: dsModels.Int32},
+			[]string{"10", "high", "high", "11"}, []bool{true, true, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			e, err := newExecutor(newScheduler(1, 0, nil), "device", contract.AutoEvent{Frequency: "1s", OnChange: true})
+			if !assert.NoError(t, err) {
+				return
+			}
+			deadbands := map[string]*deadband{"Temperature": tt.d}
+			now := time.Now()
+			for i, value := range tt.values {
+				evt := &dsModels.Event{Event: contract.Event{Readings: []contract.Reading{{Name: "Temperature", Value: value}}}}
+				assert.Equal(t, tt.sent[i], filterEvent(e, evt, deadbands, now) != nil, "reading %d: %s", i, value)
+			}
+		})
+	}
+}
+
+// newDeadbandEvent returns an event with a Temperature and a State reading.
+func newDeadbandEvent(temperature string, state string) *dsModels.Event {
+	evt := newTestEvent(temperature, state)
+	evt.Readings[0].Name = "Temperature"
+	evt.Readings[1].Name = "State"
+	return evt
+}
+
+func TestFilterEventDeadbandReportedWithEvent(t *testing.T) {
+	e, err := newExecutor(newScheduler(1, 0, nil), "device", contract.AutoEvent{Frequency: "1s", OnChange: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	deadbands := map[string]*deadband{"Temperature": {absolute: 1, valueType: dsModels.Int32}}
+	now := time.Now()
+
+	assert.NotNil(t, filterEvent(e, newDeadbandEvent("10", "on"), deadbands, now))
+	// the Temperature is reported with the change of the State, the deadband
+	// applies from 11 afterwards
+	assert.Equal(t, []string{"11", "off"}, readingValues(filterEvent(e, newDeadbandEvent("11", "off"), deadbands, now)))
+	assert.Nil(t, filterEvent(e, newDeadbandEvent("12", "off"), deadbands, now))
+	assert.NotNil(t, filterEvent(e, newDeadbandEvent("13", "off"), deadbands, now))
+}
+
+func TestFilterEventDeadbandChangedReadingsOnlyMaxSilence(t *testing.T) {
+	s := newScheduler(1, 0, nil)
+	s.changedOnly = true
+	s.maxSilence = 10 * time.Second
+	e, err := newExecutor(s, "device", contract.AutoEvent{Frequency: "1s", OnChange: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	deadbands := map[string]*deadband{"Temperature": {absolute: 1, valueType: dsModels.Float64, floatEncoding: contract.ENotation}}
+
+	start := time.Now()
+	tests := []struct {
+		temperature string
+		state       string
+		time        time.Duration
+		expected    []string
+	}{
+		{"10.0", "on", 0, []string{"10.0", "on"}},
+		// a change within the deadband isn't a change
+		{"10.5", "on", 2 * time.Second, nil},
+		// only the readings changed beyond their deadband are sent
+		{"12.0", "on", 4 * time.Second, []string{"12.0"}},
+		{"12.5", "off", 6 * time.Second, []string{"off"}},
+		// all the readings are sent after MaxSilence, even within the deadband
+		{"12.5", "off", 10 * time.Second, []string{"12.5", "off"}},
+		// the deadband applies from the value sent with all the readings
+		{"13.2", "off", 12 * time.Second, nil},
+		{"13.6", "off", 14 * time.Second, []string{"13.6"}},
+	}
+	for i, tt := range tests {
+		filtered := filterEvent(e, newDeadbandEvent(tt.temperature, tt.state), deadbands, start.Add(tt.time))
+		assert.Equal(t, tt.expected, readingValues(filtered), "event %d", i)
+	}
+}
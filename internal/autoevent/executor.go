@@ -1,12 +1,13 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
-// Copyright (C) 2019 IOTech Ltd
+// Copyright (C) 2019-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
 package autoevent
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -16,116 +17,305 @@ import (
 	"github.com/edgexfoundry/device-sdk-go/internal/handler"
 	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/google/uuid"
 )
 
+// Executor runs an AutoEvent of a Device on the AutoEvent scheduler.
 type Executor interface {
+	// Run schedules the AutoEvent, its first run is on the first tick of its
+	// Frequency.
 	Run()
+	// Stop unschedules the AutoEvent, no run starts once Stop returns and the
+	// event of a run in progress isn't sent.
 	Stop()
+	// Pause unschedules the AutoEvent until Resume is called, a run in
+	// progress finishes.
+	Pause()
+	// Resume schedules the paused AutoEvent again, its next run is on the
+	// next tick of its Frequency.
+	Resume()
+	// Trigger runs the AutoEvent now, out of its schedule.
+	Trigger() common.AppError
+	// OverrideFrequency runs the AutoEvent on the frequency rather than on its
+	// Frequency until the expiry, or until the Executor is stopped if the
+	// expiry is zero. An empty frequency removes the override.
+	OverrideFrequency(frequency string, expiry time.Time) common.AppError
+	// Status returns the status of the AutoEvent.
+	Status() ExecutorStatus
+}
+
+// The results of the runs of the AutoEvents.
+const (
+	// ResultSent means the event has been sent.
+	ResultSent = "sent"
+	// ResultUnchanged means the readings of an OnChange AutoEvent haven't
+	// changed, no event has been sent.
+	ResultUnchanged = "unchanged"
+	// ResultNoEvent means the read returned no readings.
+	ResultNoEvent = "noEvent"
+	// ResultError means the read failed.
+	ResultError = "error"
+)
+
+// ExecutorStatus is the status of an AutoEvent of a Device.
+type ExecutorStatus struct {
+	Device   string `json:"device"`
+	Resource string `json:"resource"`
+	// Frequency is the Frequency of the AutoEvent, the AutoEvent runs on the
+	// FrequencyOverride instead until the OverrideExpiry if it's set.
+	Frequency         string     `json:"frequency"`
+	FrequencyOverride string     `json:"frequencyOverride,omitempty"`
+	OverrideExpiry    *time.Time `json:"overrideExpiry,omitempty"`
+	OnChange          bool       `json:"onChange"`
+	Paused            bool       `json:"paused"`
+	Running           bool       `json:"running"`
+	// NextRun is the time of the next scheduled run, it's not set while the
+	// AutoEvent is paused.
+	NextRun    *time.Time `json:"nextRun,omitempty"`
+	LastRun    *time.Time `json:"lastRun,omitempty"`
+	LastResult string     `json:"lastResult,omitempty"`
+	LastError  string     `json:"lastError,omitempty"`
+	ErrorCount uint64     `json:"errorCount"`
 }
 
 type executor struct {
 	deviceName   string
 	autoEvent    contract.AutoEvent
 	lastReadings map[string]interface{}
-	duration     time.Duration
-	stop         bool
-	rwmutex      sync.RWMutex
+	// lastFull is the time of the last event of an OnChange AutoEvent with
+	// all the readings
+	lastFull time.Time
+	schedule schedule
+	rwmutex  sync.RWMutex
+
+	// the fields below are guarded by the mutex of the scheduler
+	scheduler *scheduler
+	// tick is the time of the next run on the schedule, due is the time of
+	// the next run including the jitter.
+	tick  time.Time
+	due   time.Time
+	index int
+	// pending is whether the executor is on the ready list of the scheduler,
+	// running whether a worker is running it
+	pending bool
+	running bool
+	stopped bool
+	paused  bool
+	// override is the schedule of the overriding frequency until the expiry,
+	// nil if the Frequency isn't overridden
+	override          schedule
+	overrideFrequency string
+	overrideExpiry    time.Time
+	// the outcome of the runs
+	lastRun    time.Time
+	lastResult string
+	lastError  string
+	errorCount uint64
 }
 
-// Run triggers this Executor executes the handler for the resource periodically
+// Run schedules this Executor on the AutoEvent scheduler
 func (e *executor) Run() {
-	for {
-		if e.stop {
-			break
-		}
-		time.Sleep(e.duration)
-
-		common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - executing %v", e.autoEvent))
-		evt, appErr := readResource(e)
-		if appErr != nil {
-			common.LoggingClient.Error(fmt.Sprintf("AutoEvent - error occurs when reading resource %s",
-				e.autoEvent.Resource))
-			continue
-		}
+	e.scheduler.schedule(e)
+}
 
-		if evt != nil {
-			if e.autoEvent.OnChange {
-				if compareReadings(e, evt.Readings, evt.HasBinaryValue()) {
-					common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - readings are the same as previous one %v", e.lastReadings))
-					continue
-				}
-			}
-			common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - pushing event %s", evt.String()))
-			event := &dsModels.Event{Event: evt.Event}
-			// Attach origin timestamp for events if none yet specified
-			if event.Origin == 0 {
-				event.Origin = common.GetUniqueOrigin()
-			}
-			go common.SendEvent(event)
-		} else {
-			common.LoggingClient.Info(fmt.Sprintf("AutoEvent - no event generated when reading resource %s",
-				e.autoEvent.Resource))
-			continue
+// runExecutor reads the resource of the AutoEvent and sends the event, it's
+// called by the workers of the scheduler with the context of the scheduler.
+func runExecutor(ctx context.Context, e *executor) {
+	start := time.Now()
+	result, errMsg := execute(ctx, e)
+	if result != "" {
+		e.scheduler.record(e, start, result, errMsg)
+	}
+}
+
+// execute runs the AutoEvent, it returns the result of the run, empty if the
+// executor or the scheduler has been stopped in the meantime, and the error
+// message of a failed run.
+func execute(ctx context.Context, e *executor) (string, string) {
+	common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - executing %v", e.autoEvent))
+	evt, appErr := readResource(ctx, e)
+	if appErr != nil {
+		common.LoggingClient.Error(fmt.Sprintf("AutoEvent - error occurs when reading resource %s",
+			e.autoEvent.Resource))
+		return ResultError, appErr.Message()
+	}
+
+	if evt == nil {
+		common.LoggingClient.Info(fmt.Sprintf("AutoEvent - no event generated when reading resource %s",
+			e.autoEvent.Resource))
+		return ResultNoEvent, ""
+	}
+	if e.autoEvent.OnChange {
+		deadbands := resourceDeadbands(e.deviceName, evt.Readings)
+		evt = filterEvent(e, evt, deadbands, time.Now())
+		if evt == nil {
+			common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - readings of %v are the same as the previous ones", e.autoEvent))
+			return ResultUnchanged, ""
 		}
 	}
+	common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - pushing event %s", evt.String()))
+	// Attach origin timestamp for events if none yet specified
+	if evt.Origin == 0 {
+		evt.Origin = common.GetUniqueOrigin()
+	}
+	if !e.scheduler.send(ctx, e, evt) {
+		common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - %v has been stopped, dropping the event", e.autoEvent))
+		return "", ""
+	}
+	return ResultSent, ""
 }
 
-func readResource(e *executor) (*dsModels.Event, common.AppError) {
+func readResource(ctx context.Context, e *executor) (*dsModels.Event, common.AppError) {
 	vars := make(map[string]string, 2)
 	vars[common.NameVar] = e.deviceName
 	vars[common.CommandVar] = e.autoEvent.Resource
 
-	evt, appErr := handler.CommandHandler(vars, "", common.GetCmdMethod, "")
+	ctx = context.WithValue(ctx, common.CorrelationHeader, uuid.New().String())
+	evt, appErr := handler.CommandHandler(ctx, vars, "", "", common.GetCmdMethod, "")
 	return evt, appErr
 }
 
-func compareReadings(e *executor, readings []contract.Reading, hasBinary bool) bool {
-	var identical bool = true
-	e.rwmutex.RLock()
-	defer e.rwmutex.RUnlock()
-	for _, r := range readings {
-		switch e.lastReadings[r.Name].(type) {
-		case uint64:
-			checksum := xxhash.Checksum64(r.BinaryValue)
-			if e.lastReadings[r.Name] != checksum {
-				e.lastReadings[r.Name] = checksum
-				identical = false
-			}
-		case string:
-			v, ok := e.lastReadings[r.Name]
-			if !ok || v != r.Value {
-				e.lastReadings[r.Name] = r.Value
-				identical = false
-			}
-		case nil:
-			if hasBinary && len(r.BinaryValue) > 0 {
-				e.lastReadings[r.Name] = xxhash.Checksum64(r.BinaryValue)
-			} else {
-				e.lastReadings[r.Name] = r.Value
+// filterEvent returns the event of an OnChange AutoEvent to send, nil if none
+// of its readings changed. The event only contains the changed readings if
+// the ChangedReadingsOnly setting says so, and all the readings if no event
+// with all the readings has been sent for MaxSilence.
+func filterEvent(e *executor, evt *dsModels.Event, deadbands map[string]*deadband, now time.Time) *dsModels.Event {
+	e.rwmutex.Lock()
+	defer e.rwmutex.Unlock()
+	values, changed := e.readingChanges(evt.Readings, evt.HasBinaryValue(), deadbands)
+	var indexes []int
+	for i := range changed {
+		if changed[i] {
+			indexes = append(indexes, i)
+		}
+	}
+
+	maxSilence := e.scheduler.maxSilence
+	switch {
+	case maxSilence > 0 && !e.lastFull.IsZero() && now.Sub(e.lastFull) >= maxSilence:
+		common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - no full event of %v for %v, sending all the readings", e.autoEvent, maxSilence))
+	case len(indexes) == 0:
+		return nil
+	case len(indexes) < len(evt.Readings) && e.scheduler.changedOnly:
+		e.report(evt.Readings, values, changed)
+		return selectReadings(evt, indexes)
+	}
+
+	e.lastFull = now
+	e.report(evt.Readings, values, nil)
+	return evt
+}
+
+// readingChanges returns the values the readings are compared by and whether
+// each reading changed since it was last reported, it must be called with the
+// mutex locked. The binary readings are compared by checksum and the numeric
+// readings with a deadband by value, the other readings by their string form.
+func (e *executor) readingChanges(readings []contract.Reading, hasBinary bool, deadbands map[string]*deadband) ([]interface{}, []bool) {
+	values := make([]interface{}, len(readings))
+	changed := make([]bool, len(readings))
+	for i, r := range readings {
+		last, ok := e.lastReadings[r.Name]
+		d := deadbands[r.Name]
+		switch {
+		case hasBinary && len(r.BinaryValue) > 0:
+			values[i] = xxhash.Checksum64(r.BinaryValue)
+		case d != nil:
+			if v, isNumber := d.value(r); isNumber {
+				values[i] = v
+				lastValue, isFloat := last.(float64)
+				changed[i] = !isFloat || d.exceeded(lastValue, v)
+				continue
 			}
-			identical = false
+			values[i] = r.Value
 		default:
-			common.LoggingClient.Error("Error: unsupported reading type (%T) in autoevent - %v\n", e.lastReadings[r.Name], e.autoEvent)
-			identical = false
+			values[i] = r.Value
 		}
+		changed[i] = !ok || last != values[i]
 	}
-	return identical
+	return values, changed
 }
 
-// Stop marks this Executor stopped
+// report records the values of the reported readings, the changed ones or all
+// of them if changed is nil, a deadband applies from the reported value. It
+// must be called with the mutex locked.
+func (e *executor) report(readings []contract.Reading, values []interface{}, changed []bool) {
+	for i, r := range readings {
+		if changed == nil || changed[i] {
+			e.lastReadings[r.Name] = values[i]
+		}
+	}
+}
+
+// selectReadings returns a copy of the event with the readings at the indexes.
+func selectReadings(evt *dsModels.Event, indexes []int) *dsModels.Event {
+	selected := *evt
+	selected.EncodedEvent = nil
+	selected.Readings = make([]contract.Reading, len(indexes))
+	if len(evt.Qualities) > 0 {
+		selected.Qualities = make([]dsModels.Quality, len(indexes))
+	}
+	for i, index := range indexes {
+		selected.Readings[i] = evt.Readings[index]
+		if index < len(evt.Qualities) {
+			selected.Qualities[i] = evt.Qualities[index]
+		}
+	}
+	return &selected
+}
+
+// Stop unschedules this Executor
 func (e *executor) Stop() {
-	e.stop = true
+	e.scheduler.unschedule(e)
+}
+
+// Pause unschedules this Executor until it's resumed
+func (e *executor) Pause() {
+	e.scheduler.pause(e)
+}
+
+// Resume schedules this paused Executor again
+func (e *executor) Resume() {
+	e.scheduler.resume(e)
+}
+
+// Trigger runs this Executor now
+func (e *executor) Trigger() common.AppError {
+	return e.scheduler.trigger(e)
+}
+
+// OverrideFrequency runs this Executor on the frequency until the expiry
+func (e *executor) OverrideFrequency(frequency string, expiry time.Time) common.AppError {
+	if frequency == "" {
+		e.scheduler.overrideSchedule(e, nil, "", time.Time{})
+		return nil
+	}
+
+	override, err := parseSchedule(frequency)
+	if err != nil {
+		return common.NewBadRequestError(fmt.Sprintf("frequency %s cannot be parsed, %v", frequency, err), err)
+	}
+	e.scheduler.overrideSchedule(e, override, frequency, expiry)
+	return nil
+}
+
+// Status returns the status of this Executor
+func (e *executor) Status() ExecutorStatus {
+	return e.scheduler.status(e)
 }
 
 // NewExecutor creates an Executor for an AutoEvent
 func NewExecutor(deviceName string, ae contract.AutoEvent) (Executor, error) {
+	return newExecutor(defaultScheduler(), deviceName, ae)
+}
+
+func newExecutor(s *scheduler, deviceName string, ae contract.AutoEvent) (*executor, error) {
 	// check Frequency
-	duration, err := time.ParseDuration(ae.Frequency)
+	sched, err := parseSchedule(ae.Frequency)
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("AutoEvent Frequency %s cannot be parsed error, %v", ae.Frequency, err))
 		return nil, err
 	}
 
 	return &executor{deviceName: deviceName, autoEvent: ae,
-		lastReadings: make(map[string]interface{}), duration: duration, stop: false}, nil
+		lastReadings: make(map[string]interface{}), schedule: sched, scheduler: s, index: -1}, nil
 }
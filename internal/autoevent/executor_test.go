@@ -1,6 +1,6 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
-// Copyright (C) 2019 IOTech Ltd
+// Copyright (C) 2019-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -8,68 +8,113 @@ package autoevent
 
 import (
 	"testing"
+	"time"
 
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
 )
 
-func TestCompareReadings(t *testing.T) {
+func TestFilterEventChanges(t *testing.T) {
 	readings := make([]contract.Reading, 4)
 	readings[0] = contract.Reading{Name: "Temperature", Value: "10"}
 	readings[1] = contract.Reading{Name: "Humidity", Value: "50"}
 	readings[2] = contract.Reading{Name: "Pressure", Value: "3"}
 	readings[3] = contract.Reading{Name: "Image", BinaryValue: []byte("This is a image")}
-
-	autoEvent := contract.AutoEvent{Frequency: "500ms"}
-	e, err := NewExecutor("hasBinaryTrue", autoEvent)
-	if err != nil {
-		t.Errorf("Autoevent executor creation failed: %v", err)
+	newEvent := func() *dsModels.Event {
+		evt := &dsModels.Event{Event: contract.Event{Device: "device"}}
+		evt.Readings = append(evt.Readings, readings...)
+		return evt
 	}
-	resultFalse := compareReadings(e.(*executor), readings, true)
-	if resultFalse {
-		t.Error("compare readings with cache failed, the result should be false in the first place")
+
+	e, err := newExecutor(newScheduler(1, 0, nil), "device", contract.AutoEvent{Frequency: "500ms", OnChange: true})
+	if !assert.NoError(t, err) {
+		return
 	}
+	now := time.Now()
+	assert.NotNil(t, filterEvent(e, newEvent(), nil, now), "the first event is always sent")
 
 	readings[1] = contract.Reading{Name: "Humidity", Value: "51"}
-	resultFalse = compareReadings(e.(*executor), readings, true)
-	if resultFalse {
-		t.Error("compare readings with cache failed, the result should be false")
-	}
+	assert.NotNil(t, filterEvent(e, newEvent(), nil, now), "the changed Humidity should be sent")
 
+	// the binary readings are compared by checksum
 	readings[3] = contract.Reading{Name: "Image", BinaryValue: []byte("This is not a image")}
-	resultFalse = compareReadings(e.(*executor), readings, true)
-	if resultFalse {
-		t.Error("compare readings with cache failed, the result should be false")
-	}
+	assert.NotNil(t, filterEvent(e, newEvent(), nil, now), "the changed Image should be sent")
 
-	resultTrue := compareReadings(e.(*executor), readings, true)
-	if !resultTrue {
-		t.Error("compare readings with cache failed, the result should be true with unchanged readings")
-	}
-
-	e, err = NewExecutor("hasBinaryFalse", autoEvent)
-	if err != nil {
-		t.Errorf("Autoevent executor creation failed: %v", err)
-	}
-	// This scenario should not happen in real case
-	resultFalse = compareReadings(e.(*executor), readings, false)
-	if resultFalse {
-		t.Error("compare readings with cache failed, the result should be false in the first place")
-	}
+	assert.Nil(t, filterEvent(e, newEvent(), nil, now), "the unchanged readings shouldn't be sent")
 
+	readings = readings[:3]
 	readings[0] = contract.Reading{Name: "Temperature", Value: "20"}
-	resultFalse = compareReadings(e.(*executor), readings, false)
-	if resultFalse {
-		t.Error("compare readings with cache failed, the result should be false")
+	assert.NotNil(t, filterEvent(e, newEvent(), nil, now), "the changed Temperature should be sent")
+	assert.Nil(t, filterEvent(e, newEvent(), nil, now), "the unchanged readings shouldn't be sent")
+}
+
+func newTestEvent(values ...string) *dsModels.Event {
+	names := []string{"RotationX", "RotationY", "RotationZ"}
+	evt := &dsModels.Event{Event: contract.Event{Device: "device"}}
+	for i, v := range values {
+		evt.Readings = append(evt.Readings, contract.Reading{Name: names[i], Value: v})
+		evt.Qualities = append(evt.Qualities, dsModels.Quality{Status: dsModels.QualityStatus(v)})
 	}
+	return evt
+}
 
-	readings[3] = contract.Reading{Name: "Image", BinaryValue: []byte("This is a image")}
-	resultTrue = compareReadings(e.(*executor), readings, false)
-	if !resultTrue {
-		t.Error("compare readings with cache failed, the result should always be true in such scenario")
+func readingValues(evt *dsModels.Event) []string {
+	if evt == nil {
+		return nil
 	}
+	var values []string
+	for i, r := range evt.Readings {
+		values = append(values, r.Value)
+		// the qualities stay with their readings
+		if string(evt.Qualities[i].Status) != r.Value {
+			return nil
+		}
+	}
+	return values
+}
 
-	resultTrue = compareReadings(e.(*executor), readings, false)
-	if !resultTrue {
-		t.Error("compare readings with cache failed, the result should be true with unchanged readings")
+func TestFilterEvent(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		testName    string
+		changedOnly bool
+		maxSilence  time.Duration
+		events      []*dsModels.Event
+		times       []time.Duration
+		expected    [][]string
+	}{
+		{"AllReadings", false, 0,
+			[]*dsModels.Event{newTestEvent("1", "2", "3"), newTestEvent("1", "2", "3"), newTestEvent("1", "5", "3")},
+			[]time.Duration{0, time.Second, 2 * time.Second},
+			[][]string{{"1", "2", "3"}, nil, {"1", "5", "3"}}},
+		{"ChangedReadingsOnly", true, 0,
+			[]*dsModels.Event{newTestEvent("1", "2", "3"), newTestEvent("1", "5", "3"), newTestEvent("4", "5", "6"), newTestEvent("4", "5", "6")},
+			[]time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second},
+			[][]string{{"1", "2", "3"}, {"5"}, {"4", "6"}, nil}},
+		{"MaxSilence", false, 10 * time.Second,
+			[]*dsModels.Event{newTestEvent("1", "2"), newTestEvent("1", "2"), newTestEvent("1", "2"), newTestEvent("1", "2")},
+			[]time.Duration{0, 5 * time.Second, 10 * time.Second, 15 * time.Second},
+			[][]string{{"1", "2"}, nil, {"1", "2"}, nil}},
+		// the events with only the changed readings don't reset the MaxSilence
+		{"ChangedReadingsOnlyMaxSilence", true, 10 * time.Second,
+			[]*dsModels.Event{newTestEvent("1", "2"), newTestEvent("3", "2"), newTestEvent("3", "2"), newTestEvent("3", "4")},
+			[]time.Duration{0, 5 * time.Second, 10 * time.Second, 12 * time.Second},
+			[][]string{{"1", "2"}, {"3"}, {"3", "2"}, {"4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			s := newScheduler(1, 0, nil)
+			s.changedOnly = tt.changedOnly
+			s.maxSilence = tt.maxSilence
+			e, err := newExecutor(s, "device", contract.AutoEvent{Frequency: "1s", OnChange: true})
+			if !assert.NoError(t, err) {
+				return
+			}
+			for i, evt := range tt.events {
+				filtered := filterEvent(e, evt, nil, start.Add(tt.times[i]))
+				assert.Equal(t, tt.expected[i], readingValues(filtered), "event %d", i)
+			}
+		})
 	}
 }
@@ -1,6 +1,6 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
-// Copyright (C) 2019 IOTech Ltd
+// Copyright (C) 2019-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -9,7 +9,9 @@ package autoevent
 import (
 	"fmt"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
@@ -20,6 +22,20 @@ type Manager interface {
 	StopAutoEvents()
 	RestartForDevice(deviceName string)
 	StopForDevice(deviceName string)
+	// Status returns the status of the AutoEvents of the Device, or of all the
+	// Devices if deviceName is empty.
+	Status(deviceName string) ([]ExecutorStatus, common.AppError)
+	// Pause pauses the AutoEvents of the resource of the Device.
+	Pause(deviceName string, resource string) common.AppError
+	// Resume resumes the paused AutoEvents of the resource of the Device.
+	Resume(deviceName string, resource string) common.AppError
+	// Trigger runs the AutoEvents of the resource of the Device now.
+	Trigger(deviceName string, resource string) common.AppError
+	// OverrideFrequency runs the AutoEvents of the resource of the Device on
+	// the frequency for the duration, or until the AutoEvents of the Device are
+	// restarted if the duration is zero. An empty frequency removes the
+	// override.
+	OverrideFrequency(deviceName string, resource string, frequency string, duration time.Duration) common.AppError
 }
 
 var (
@@ -44,6 +60,8 @@ func (m *manager) StartAutoEvents() {
 	mutex.Unlock()
 }
 
+// StopAutoEvents stops all the AutoEvents and the scheduler running them, the
+// runs in progress are canceled.
 func (m *manager) StopAutoEvents() {
 	mutex.Lock()
 	for k, v := range m.execsMap {
@@ -53,6 +71,7 @@ func (m *manager) StopAutoEvents() {
 		delete(m.execsMap, k)
 	}
 	mutex.Unlock()
+	defaultScheduler().stop()
 }
 
 func triggerExecutors(deviceName string, autoEvents []contract.AutoEvent) []Executor {
@@ -65,7 +84,7 @@ func triggerExecutors(deviceName string, autoEvents []contract.AutoEvent) []Exec
 			continue
 		}
 		execs = append(execs, exec)
-		go exec.Run()
+		exec.Run()
 	}
 	return execs
 }
@@ -97,6 +116,93 @@ func (m *manager) StopForDevice(deviceName string) {
 	mutex.Unlock()
 }
 
+func (m *manager) Status(deviceName string) ([]ExecutorStatus, common.AppError) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var names []string
+	if deviceName == "" {
+		for name := range m.execsMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	} else if _, ok := m.execsMap[deviceName]; ok {
+		names = append(names, deviceName)
+	} else if _, ok := cache.Devices().ForName(deviceName); !ok {
+		msg := fmt.Sprintf("Device %s cannot be found in cache", deviceName)
+		return nil, common.NewNotFoundError(msg, nil)
+	}
+
+	statuses := make([]ExecutorStatus, 0)
+	for _, name := range names {
+		for _, e := range m.execsMap[name] {
+			statuses = append(statuses, e.Status())
+		}
+	}
+	return statuses, nil
+}
+
+func (m *manager) Pause(deviceName string, resource string) common.AppError {
+	return m.forExecutors(deviceName, resource, func(e Executor) common.AppError {
+		e.Pause()
+		return nil
+	})
+}
+
+func (m *manager) Resume(deviceName string, resource string) common.AppError {
+	return m.forExecutors(deviceName, resource, func(e Executor) common.AppError {
+		e.Resume()
+		return nil
+	})
+}
+
+func (m *manager) Trigger(deviceName string, resource string) common.AppError {
+	return m.forExecutors(deviceName, resource, Executor.Trigger)
+}
+
+func (m *manager) OverrideFrequency(deviceName string, resource string, frequency string, duration time.Duration) common.AppError {
+	if duration < 0 {
+		msg := fmt.Sprintf("the duration %v of the frequency override cannot be negative", duration)
+		return common.NewBadRequestError(msg, nil)
+	} else if frequency != "" {
+		if _, err := parseSchedule(frequency); err != nil {
+			msg := fmt.Sprintf("frequency %s cannot be parsed, %v", frequency, err)
+			return common.NewBadRequestError(msg, err)
+		}
+	}
+
+	var expiry time.Time
+	if duration > 0 {
+		expiry = time.Now().Add(duration)
+	}
+	return m.forExecutors(deviceName, resource, func(e Executor) common.AppError {
+		return e.OverrideFrequency(frequency, expiry)
+	})
+}
+
+// forExecutors calls f for each executor of the AutoEvents of the resource of
+// the Device, it stops at the first error.
+func (m *manager) forExecutors(deviceName string, resource string, f func(e Executor) common.AppError) common.AppError {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	found := false
+	for _, e := range m.execsMap[deviceName] {
+		if e.Status().Resource != resource {
+			continue
+		}
+		found = true
+		if appErr := f(e); appErr != nil {
+			return appErr
+		}
+	}
+	if !found {
+		msg := fmt.Sprintf("there is no AutoEvent of resource %s for Device %s", resource, deviceName)
+		return common.NewNotFoundError(msg, nil)
+	}
+	return nil
+}
+
 // GetManager initiates the AutoEvent manager once and returns its instance
 func GetManager() Manager {
 	createOnce.Do(func() {
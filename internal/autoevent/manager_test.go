@@ -0,0 +1,102 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManager(t *testing.T, s *scheduler) *manager {
+	m := &manager{execsMap: make(map[string][]Executor)}
+	for _, ae := range []struct {
+		device   string
+		resource string
+	}{{"device2", "temperature"}, {"device1", "temperature"}, {"device1", "humidity"}} {
+		e, err := newExecutor(s, ae.device, contract.AutoEvent{Frequency: "1h", Resource: ae.resource})
+		if err != nil {
+			t.Fatalf("Autoevent executor creation failed: %v", err)
+		}
+		m.execsMap[ae.device] = append(m.execsMap[ae.device], e)
+	}
+	return m
+}
+
+func TestManagerStatus(t *testing.T) {
+	m := newTestManager(t, newScheduler(1, 0, nil))
+
+	statuses, appErr := m.Status("")
+	assert.Nil(t, appErr)
+	var names []string
+	for _, s := range statuses {
+		names = append(names, s.Device+"/"+s.Resource)
+	}
+	assert.Equal(t, []string{"device1/temperature", "device1/humidity", "device2/temperature"}, names)
+
+	statuses, appErr = m.Status("device2")
+	assert.Nil(t, appErr)
+	assert.Len(t, statuses, 1)
+}
+
+func TestManagerActions(t *testing.T) {
+	recorder := newRunRecorder(0)
+	s := newScheduler(1, 0, recorder.run)
+	m := newTestManager(t, s)
+	for _, execs := range m.execsMap {
+		for _, e := range execs {
+			e.Run()
+		}
+	}
+	temperature := m.execsMap["device1"][0].(*executor)
+
+	assert.Nil(t, m.Pause("device1", "temperature"))
+	assert.True(t, temperature.Status().Paused)
+	assert.False(t, m.execsMap["device2"][0].Status().Paused, "only the AutoEvents of the Device are paused")
+	assert.Nil(t, m.Resume("device1", "temperature"))
+	assert.False(t, temperature.Status().Paused)
+
+	assert.Nil(t, m.Trigger("device1", "temperature"))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, recorder.count(temperature))
+
+	assert.Nil(t, m.OverrideFrequency("device1", "temperature", "cron:0 * * * *", time.Hour))
+	status := temperature.Status()
+	assert.Equal(t, "cron:0 * * * *", status.FrequencyOverride)
+	assert.NotNil(t, status.OverrideExpiry)
+	assert.Nil(t, m.OverrideFrequency("device1", "temperature", "", 0))
+	assert.Empty(t, temperature.Status().FrequencyOverride)
+
+	tests := []struct {
+		testName string
+		action   func() common.AppError
+		code     int
+	}{
+		{"UnknownDevice", func() common.AppError { return m.Pause("device3", "temperature") }, http.StatusNotFound},
+		{"UnknownResource", func() common.AppError { return m.Trigger("device2", "humidity") }, http.StatusNotFound},
+		{"InvalidFrequency", func() common.AppError { return m.OverrideFrequency("device1", "temperature", "often", 0) }, http.StatusBadRequest},
+		{"NegativeDuration", func() common.AppError { return m.OverrideFrequency("device1", "temperature", "1s", -time.Second) }, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			appErr := tt.action()
+			if assert.NotNil(t, appErr) {
+				assert.Equal(t, tt.code, appErr.Code())
+			}
+		})
+	}
+
+	for _, execs := range m.execsMap {
+		for _, e := range execs {
+			e.Stop()
+		}
+	}
+}
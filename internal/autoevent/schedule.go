@@ -0,0 +1,55 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CronPrefix marks an AutoEvent Frequency as a cron expression, e.g.
+// "cron:0 2 * * *". The Frequencies starting with "@", e.g. "@hourly", are
+// cron expressions as well, the other Frequencies are Go durations.
+const CronPrefix = "cron:"
+
+// schedule computes the ticks an AutoEvent runs on.
+type schedule interface {
+	// next returns the first tick after both the previous tick and now, the
+	// ticks missed before now are skipped. The first tick is next(now, now).
+	next(tick time.Time, now time.Time) time.Time
+}
+
+// intervalSchedule runs every duration, from the time the AutoEvent is scheduled.
+type intervalSchedule time.Duration
+
+func (d intervalSchedule) next(tick time.Time, now time.Time) time.Time {
+	duration := time.Duration(d)
+	tick = tick.Add(duration)
+	if !tick.After(now) {
+		missed := now.Sub(tick)/duration + 1
+		tick = tick.Add(missed * duration)
+	}
+	return tick
+}
+
+// parseSchedule parses the Frequency of an AutoEvent, either a cron
+// expression or a duration.
+func parseSchedule(frequency string) (schedule, error) {
+	f := strings.TrimSpace(frequency)
+	if strings.HasPrefix(f, CronPrefix) || strings.HasPrefix(f, "@") {
+		return parseCron(strings.TrimPrefix(f, CronPrefix))
+	}
+
+	duration, err := time.ParseDuration(f)
+	if err != nil {
+		return nil, err
+	} else if duration <= 0 {
+		return nil, fmt.Errorf("the duration %s should be greater than zero", frequency)
+	}
+	return intervalSchedule(duration), nil
+}
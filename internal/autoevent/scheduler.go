@@ -0,0 +1,461 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+const defaultWorkers = 4
+
+// scheduler runs the executors on the ticks of their schedule from a single
+// goroutine, which adds the due executors to a ready list pulled in order by a
+// fixed number of workers. The ticks are computed from the previous tick rather
+// than from the end of the previous run, so the schedule doesn't drift, and
+// only the ticks on which the previous run of an executor is still pending or
+// going on are skipped. The goroutines run from start until stop, whose
+// context is the one of the runs.
+type scheduler struct {
+	mutex sync.Mutex
+	queue executorHeap
+	// the run of an executor and the sending of its event, replaced by the
+	// tests
+	run        func(ctx context.Context, e *executor)
+	sendEvent  func(evt *dsModels.Event)
+	workers    int
+	jitter     time.Duration
+	fromConfig bool
+	rand       *rand.Rand
+	// the settings of the OnChange AutoEvents
+	changedOnly bool
+	maxSilence  time.Duration
+
+	// ready is the list of the executors waiting for a worker, readyCond
+	// signals the workers when it's added to
+	ready     []*executor
+	readyCond *sync.Cond
+	wakeCh    chan struct{}
+	// cancel stops the goroutines started by start, nil while they aren't
+	// running
+	cancel context.CancelFunc
+}
+
+var (
+	schedulerOnce sync.Once
+	sched         *scheduler
+)
+
+// defaultScheduler returns the scheduler of the AutoEvents of the service, it's
+// configured by [Device.AutoEvent] when the first executor is scheduled.
+func defaultScheduler() *scheduler {
+	schedulerOnce.Do(func() {
+		sched = newScheduler(0, 0, runExecutor)
+		sched.fromConfig = true
+	})
+	return sched
+}
+
+func newScheduler(workers int, jitter time.Duration, run func(ctx context.Context, e *executor)) *scheduler {
+	s := &scheduler{
+		run:       run,
+		sendEvent: common.SendEventAsync,
+		workers:   workers,
+		jitter:    jitter,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		wakeCh:    make(chan struct{}, 1),
+	}
+	s.readyCond = sync.NewCond(&s.mutex)
+	return s
+}
+
+func (s *scheduler) loadConfig() {
+	config := common.CurrentConfig.Device.AutoEvent
+	s.workers = config.Workers
+	if config.Jitter != "" {
+		jitter, err := time.ParseDuration(config.Jitter)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("AutoEvent Jitter %s cannot be parsed, no jitter is applied, %v", config.Jitter, err))
+		}
+		s.jitter = jitter
+	}
+	s.changedOnly = config.ChangedReadingsOnly
+	if config.MaxSilence != "" {
+		maxSilence, err := time.ParseDuration(config.MaxSilence)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("AutoEvent MaxSilence %s cannot be parsed, the OnChange AutoEvents only send changes, %v", config.MaxSilence, err))
+		}
+		s.maxSilence = maxSilence
+	}
+}
+
+// start starts the scheduling goroutine and the workers unless they are
+// running, they are idle while no executor is scheduled.
+func (s *scheduler) start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cancel != nil {
+		return
+	}
+
+	if s.fromConfig {
+		s.loadConfig()
+	}
+	if s.workers <= 0 {
+		s.workers = defaultWorkers
+	}
+	if s.jitter < 0 {
+		s.jitter = 0
+	}
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	for i := 0; i < s.workers; i++ {
+		go s.work(ctx)
+	}
+	go s.loop(ctx)
+}
+
+// stop stops the scheduling goroutine and the workers, and cancels the runs in
+// progress, whose events aren't sent. It doesn't wait for the runs to finish.
+// The executors stay scheduled and run again once the scheduler is started.
+func (s *scheduler) stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.cancel = nil
+	s.readyCond.Broadcast()
+}
+
+// schedule adds the executor to the scheduler, its first run is on the first
+// tick of its schedule.
+func (s *scheduler) schedule(e *executor) {
+	s.start()
+
+	s.mutex.Lock()
+	if e.stopped || e.paused || e.index >= 0 {
+		s.mutex.Unlock()
+		return
+	}
+	s.push(e, time.Now())
+	s.mutex.Unlock()
+	s.wake()
+}
+
+// push adds the executor to the queue, its first run is on the first tick
+// after now. It must be called with the mutex locked.
+func (s *scheduler) push(e *executor, now time.Time) {
+	e.tick = s.nextTick(e, now, now)
+	e.due = e.tick.Add(s.randomJitter())
+	heap.Push(&s.queue, e)
+}
+
+// unschedule removes the executor from the scheduler. A run in progress
+// finishes but its event isn't sent, unless it's already being handed to the
+// EventPublisher, unschedule doesn't wait for it.
+func (s *scheduler) unschedule(e *executor) {
+	s.mutex.Lock()
+	e.stopped = true
+	if e.index >= 0 {
+		heap.Remove(&s.queue, e.index)
+	}
+	s.removeReady(e)
+	s.mutex.Unlock()
+	s.wake()
+}
+
+// stopped returns whether the executor has been unscheduled.
+func (s *scheduler) stopped(e *executor) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return e.stopped
+}
+
+// send sends the event of a run of the executor unless it has been
+// unscheduled or the context of the run is done, it returns whether the event
+// is sent. No lock is held while the event is handed to the EventPublisher,
+// which can block until a send finishes.
+func (s *scheduler) send(ctx context.Context, e *executor, evt *dsModels.Event) bool {
+	if ctx.Err() != nil || s.stopped(e) {
+		return false
+	}
+	s.sendEvent(evt)
+	return true
+}
+
+func (s *scheduler) wake() {
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *scheduler) loop(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		s.mutex.Lock()
+		now := time.Now()
+		for len(s.queue) > 0 && !s.queue[0].due.After(now) {
+			s.dispatch(s.queue[0], now)
+		}
+		wait := time.Hour
+		if len(s.queue) > 0 {
+			wait = s.queue[0].due.Sub(now)
+		}
+		s.mutex.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.wakeCh:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatch adds the executor to the ready list unless its previous run is
+// still pending or going on, and schedules its next tick. It must be called
+// with the mutex locked.
+func (s *scheduler) dispatch(e *executor, now time.Time) {
+	if e.pending || e.running {
+		common.LoggingClient.Debug(fmt.Sprintf("AutoEvent - the previous run of %v is still in progress, skipping the tick", e.autoEvent))
+	} else {
+		s.addReady(e)
+	}
+
+	e.tick = s.nextTick(e, e.tick, now)
+	e.due = e.tick.Add(s.randomJitter())
+	heap.Fix(&s.queue, e.index)
+}
+
+// nextTick returns the next tick of the executor on its frequency override
+// until the override expires, on the schedule of its Frequency otherwise. It
+// must be called with the mutex locked.
+func (s *scheduler) nextTick(e *executor, tick time.Time, now time.Time) time.Time {
+	if e.override == nil {
+		return e.schedule.next(tick, now)
+	}
+	next := e.override.next(tick, now)
+	if e.overrideExpiry.IsZero() || next.Before(e.overrideExpiry) {
+		return next
+	}
+
+	// the override expires before its next tick, the schedule of the
+	// Frequency resumes from the expiry
+	common.LoggingClient.Info(fmt.Sprintf("AutoEvent - the frequency override %s of %v has expired", e.overrideFrequency, e.autoEvent))
+	expiry := e.overrideExpiry
+	e.override, e.overrideFrequency, e.overrideExpiry = nil, "", time.Time{}
+	return e.schedule.next(expiry, now)
+}
+
+// pause removes the executor from the queue and the ready list until it's
+// resumed, a run in progress finishes.
+func (s *scheduler) pause(e *executor) {
+	s.mutex.Lock()
+	e.paused = true
+	if e.index >= 0 {
+		heap.Remove(&s.queue, e.index)
+	}
+	s.removeReady(e)
+	s.mutex.Unlock()
+	s.wake()
+}
+
+// resume adds the paused executor back to the queue.
+func (s *scheduler) resume(e *executor) {
+	s.mutex.Lock()
+	if !e.paused {
+		s.mutex.Unlock()
+		return
+	}
+	e.paused = false
+	if !e.stopped {
+		s.push(e, time.Now())
+	}
+	s.mutex.Unlock()
+	s.wake()
+}
+
+// trigger adds the executor to the ready list now, its schedule is left as
+// is.
+func (s *scheduler) trigger(e *executor) common.AppError {
+	s.start()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if e.stopped {
+		return common.NewNotFoundError(fmt.Sprintf("AutoEvent %v has been stopped", e.autoEvent), nil)
+	} else if e.pending || e.running {
+		return common.NewConflictError(fmt.Sprintf("AutoEvent %v is already running", e.autoEvent), nil)
+	}
+	s.addReady(e)
+	return nil
+}
+
+// overrideSchedule replaces the schedule of the executor by the override of
+// the frequency until the expiry, or removes the override if it's nil. The
+// executor is rescheduled on the new schedule from now.
+func (s *scheduler) overrideSchedule(e *executor, override schedule, frequency string, expiry time.Time) {
+	s.mutex.Lock()
+	e.override, e.overrideFrequency, e.overrideExpiry = override, frequency, expiry
+	if e.index >= 0 {
+		now := time.Now()
+		e.tick = s.nextTick(e, now, now)
+		e.due = e.tick.Add(s.randomJitter())
+		heap.Fix(&s.queue, e.index)
+	}
+	s.mutex.Unlock()
+	s.wake()
+}
+
+// record records the result of a run of the executor along with the error
+// message of a failed run.
+func (s *scheduler) record(e *executor, at time.Time, result string, errMsg string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	e.lastRun = at
+	e.lastResult = result
+	if result == ResultError {
+		e.lastError = errMsg
+		e.errorCount++
+	}
+}
+
+// status returns the status of the executor.
+func (s *scheduler) status(e *executor) ExecutorStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	status := ExecutorStatus{
+		Device:            e.deviceName,
+		Resource:          e.autoEvent.Resource,
+		Frequency:         e.autoEvent.Frequency,
+		FrequencyOverride: e.overrideFrequency,
+		OnChange:          e.autoEvent.OnChange,
+		Paused:            e.paused,
+		Running:           e.running,
+		LastResult:        e.lastResult,
+		LastError:         e.lastError,
+		ErrorCount:        e.errorCount,
+	}
+	if !e.overrideExpiry.IsZero() {
+		expiry := e.overrideExpiry
+		status.OverrideExpiry = &expiry
+	}
+	if e.index >= 0 {
+		due := e.due
+		status.NextRun = &due
+	}
+	if !e.lastRun.IsZero() {
+		lastRun := e.lastRun
+		status.LastRun = &lastRun
+	}
+	return status
+}
+
+// randomJitter returns a random delay up to the jitter, it must be called with
+// the mutex locked.
+func (s *scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(s.rand.Int63n(int64(s.jitter)))
+}
+
+// addReady adds the executor to the end of the ready list, it must be called
+// with the mutex locked.
+func (s *scheduler) addReady(e *executor) {
+	e.pending = true
+	s.ready = append(s.ready, e)
+	s.readyCond.Signal()
+}
+
+// removeReady removes the executor from the ready list if its run is pending,
+// it must be called with the mutex locked.
+func (s *scheduler) removeReady(e *executor) {
+	if !e.pending {
+		return
+	}
+	e.pending = false
+	for i, r := range s.ready {
+		if r == e {
+			s.ready = append(s.ready[:i], s.ready[i+1:]...)
+			break
+		}
+	}
+}
+
+// work runs the executors of the ready list in order until the context is
+// done.
+func (s *scheduler) work(ctx context.Context) {
+	for {
+		s.mutex.Lock()
+		for len(s.ready) == 0 && ctx.Err() == nil {
+			s.readyCond.Wait()
+		}
+		if ctx.Err() != nil {
+			s.mutex.Unlock()
+			return
+		}
+		e := s.ready[0]
+		s.ready[0] = nil
+		s.ready = s.ready[1:]
+		e.pending, e.running = false, true
+		s.mutex.Unlock()
+
+		s.run(ctx, e)
+
+		s.mutex.Lock()
+		e.running = false
+		s.mutex.Unlock()
+	}
+}
+
+// executorHeap is a min-heap of the executors ordered by their due time.
+type executorHeap []*executor
+
+func (h executorHeap) Len() int           { return len(h) }
+func (h executorHeap) Less(i, j int) bool { return h[i].due.Before(h[j].due) }
+
+func (h executorHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *executorHeap) Push(x interface{}) {
+	e := x.(*executor)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *executorHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
@@ -0,0 +1,385 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package autoevent
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	common.LoggingClient = logger.MockLogger{}
+}
+
+// runRecorder records the runs of the executors and the maximum number of
+// concurrent runs.
+type runRecorder struct {
+	mutex   sync.Mutex
+	runs    map[*executor][]time.Time
+	delay   time.Duration
+	current int32
+	max     int32
+}
+
+func newRunRecorder(delay time.Duration) *runRecorder {
+	return &runRecorder{runs: make(map[*executor][]time.Time), delay: delay}
+}
+
+func (r *runRecorder) run(ctx context.Context, e *executor) {
+	n := atomic.AddInt32(&r.current, 1)
+	for {
+		max := atomic.LoadInt32(&r.max)
+		if n <= max || atomic.CompareAndSwapInt32(&r.max, max, n) {
+			break
+		}
+	}
+
+	r.mutex.Lock()
+	r.runs[e] = append(r.runs[e], time.Now())
+	r.mutex.Unlock()
+
+	time.Sleep(r.delay)
+	atomic.AddInt32(&r.current, -1)
+}
+
+func (r *runRecorder) count(e *executor) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.runs[e])
+}
+
+func (r *runRecorder) times(e *executor) []time.Time {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]time.Time(nil), r.runs[e]...)
+}
+
+func newTestExecutor(t *testing.T, s *scheduler, frequency string) *executor {
+	e, err := newExecutor(s, "device", contract.AutoEvent{Frequency: frequency, Resource: "resource"})
+	if err != nil {
+		t.Fatalf("Autoevent executor creation failed: %v", err)
+	}
+	return e
+}
+
+func TestSchedulerFixedRate(t *testing.T) {
+	recorder := newRunRecorder(20 * time.Millisecond)
+	s := newScheduler(2, 0, recorder.run)
+	e := newTestExecutor(t, s, "50ms")
+
+	start := time.Now()
+	e.Run()
+	time.Sleep(320 * time.Millisecond)
+	e.Stop()
+
+	times := recorder.times(e)
+	assert.True(t, len(times) >= 5, "expected 6 runs, got %d", len(times))
+	for i, at := range times {
+		// the runs stay on the ticks despite the time spent by each run, a
+		// drifting schedule would be late by 20ms more on each run, i.e. 80ms
+		// on the fifth run
+		expected := start.Add(time.Duration(i+1) * 50 * time.Millisecond)
+		assert.InDelta(t, 0, at.Sub(expected).Seconds(), (25 * time.Millisecond).Seconds(), "run %d", i)
+	}
+}
+
+func TestSchedulerSkipsOverlappingRuns(t *testing.T) {
+	recorder := newRunRecorder(35 * time.Millisecond)
+	s := newScheduler(4, 0, recorder.run)
+	e := newTestExecutor(t, s, "10ms")
+
+	e.Run()
+	time.Sleep(120 * time.Millisecond)
+	e.Stop()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&recorder.max), "the runs of an executor shouldn't overlap")
+	assert.True(t, recorder.count(e) <= 4, "the ticks during a run should be skipped, got %d runs", recorder.count(e))
+}
+
+func TestSchedulerBoundedWorkers(t *testing.T) {
+	recorder := newRunRecorder(15 * time.Millisecond)
+	s := newScheduler(2, 0, recorder.run)
+	var execs []*executor
+	for i := 0; i < 10; i++ {
+		e := newTestExecutor(t, s, "10ms")
+		execs = append(execs, e)
+		e.Run()
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	for _, e := range execs {
+		e.Stop()
+	}
+	max := atomic.LoadInt32(&recorder.max)
+	assert.True(t, max <= 2, "at most 2 runs should be concurrent, got %d", max)
+	assert.NotZero(t, max)
+	// the due executors wait for a worker rather than skipping their ticks,
+	// so none of them starves
+	for i, e := range execs {
+		assert.NotZero(t, recorder.count(e), "executor %d never ran", i)
+	}
+}
+
+func TestExecutorStop(t *testing.T) {
+	recorder := newRunRecorder(0)
+	s := newScheduler(1, 0, recorder.run)
+	e := newTestExecutor(t, s, "5ms")
+	stopped := newTestExecutor(t, s, "5ms")
+
+	stopped.Stop()
+	stopped.Run()
+	e.Run()
+	time.Sleep(30 * time.Millisecond)
+	e.Stop()
+	assert.True(t, s.stopped(e))
+
+	// wait for a run in progress to finish
+	time.Sleep(10 * time.Millisecond)
+	count := recorder.count(e)
+	assert.NotZero(t, count)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, count, recorder.count(e), "no run should start after Stop")
+	assert.Zero(t, recorder.count(stopped), "a stopped executor shouldn't be scheduled")
+	assert.Empty(t, s.queue)
+}
+
+func TestSchedulerDispatch(t *testing.T) {
+	s := newScheduler(1, 5*time.Millisecond, nil)
+	e := newTestExecutor(t, s, "10ms")
+
+	start := time.Now()
+	e.tick = start
+	e.due = start
+	s.queue.Push(e)
+
+	// the scheduler is late by 3.5 ticks, the missed ticks are skipped
+	s.dispatch(e, start.Add(35*time.Millisecond))
+	assert.True(t, e.pending)
+	assert.Equal(t, []*executor{e}, s.ready)
+	assert.Equal(t, start.Add(40*time.Millisecond), e.tick)
+	assert.True(t, !e.due.Before(e.tick) && e.due.Before(e.tick.Add(5*time.Millisecond)), "the jitter should be less than 5ms")
+
+	// the previous run is still pending, the executor isn't added again
+	s.dispatch(e, e.tick)
+	assert.Len(t, s.ready, 1)
+	assert.Equal(t, start.Add(50*time.Millisecond), e.tick)
+}
+
+func TestNewExecutorInvalidFrequency(t *testing.T) {
+	for _, frequency := range []string{"", "10", "0s", "-1s"} {
+		_, err := newExecutor(newScheduler(1, 0, nil), "device", contract.AutoEvent{Frequency: frequency})
+		assert.Error(t, err, frequency)
+	}
+}
+
+func TestSchedulerPauseResume(t *testing.T) {
+	recorder := newRunRecorder(0)
+	s := newScheduler(1, 0, recorder.run)
+	e := newTestExecutor(t, s, "5ms")
+
+	e.Run()
+	time.Sleep(30 * time.Millisecond)
+	e.Pause()
+	assert.True(t, e.Status().Paused)
+	assert.Nil(t, e.Status().NextRun, "a paused executor has no next run")
+
+	// wait for a run in progress to finish
+	time.Sleep(10 * time.Millisecond)
+	count := recorder.count(e)
+	assert.NotZero(t, count)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, count, recorder.count(e), "no run should start while paused")
+
+	e.Resume()
+	assert.NotNil(t, e.Status().NextRun)
+	time.Sleep(30 * time.Millisecond)
+	e.Stop()
+	assert.True(t, recorder.count(e) > count, "the runs should start again once resumed")
+}
+
+func TestSchedulerTrigger(t *testing.T) {
+	recorder := newRunRecorder(20 * time.Millisecond)
+	s := newScheduler(1, 0, recorder.run)
+	e := newTestExecutor(t, s, "1h")
+	other := newTestExecutor(t, s, "1h")
+	e.Run()
+
+	assert.Nil(t, e.Trigger())
+	time.Sleep(5 * time.Millisecond)
+	appErr := e.Trigger()
+	if assert.NotNil(t, appErr, "the executor is already running") {
+		assert.Equal(t, http.StatusConflict, appErr.Code())
+	}
+	// the only worker is busy, the run of the other executor waits for it
+	assert.Nil(t, other.Trigger())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 1, recorder.count(e))
+	assert.Equal(t, 1, recorder.count(other))
+
+	e.Stop()
+	appErr = e.Trigger()
+	if assert.NotNil(t, appErr, "a stopped executor cannot be triggered") {
+		assert.Equal(t, http.StatusNotFound, appErr.Code())
+	}
+}
+
+func TestSchedulerOverrideFrequency(t *testing.T) {
+	s := newScheduler(1, 0, nil)
+	e := newTestExecutor(t, s, "1m")
+	start := time.Now()
+
+	appErr := e.OverrideFrequency("10s", start.Add(25*time.Second))
+	assert.Nil(t, appErr)
+	assert.Equal(t, "10s", e.Status().FrequencyOverride)
+	assert.Equal(t, start.Add(10*time.Second), s.nextTick(e, start, start))
+	assert.Equal(t, start.Add(20*time.Second), s.nextTick(e, start.Add(10*time.Second), start.Add(10*time.Second)))
+	// the override expires before its next tick, the Frequency resumes from
+	// the expiry
+	assert.Equal(t, start.Add(85*time.Second), s.nextTick(e, start.Add(20*time.Second), start.Add(20*time.Second)))
+	assert.Empty(t, e.Status().FrequencyOverride)
+	assert.Nil(t, e.Status().OverrideExpiry)
+
+	assert.Nil(t, e.OverrideFrequency("10s", time.Time{}))
+	assert.Equal(t, start.Add(10*time.Second), s.nextTick(e, start, start))
+	assert.Nil(t, e.OverrideFrequency("", time.Time{}))
+	assert.Equal(t, start.Add(time.Minute), s.nextTick(e, start, start))
+
+	appErr = e.OverrideFrequency("often", time.Time{})
+	if assert.NotNil(t, appErr) {
+		assert.Equal(t, http.StatusBadRequest, appErr.Code())
+	}
+}
+
+func TestSchedulerOverrideReschedules(t *testing.T) {
+	recorder := newRunRecorder(0)
+	s := newScheduler(1, 0, recorder.run)
+	e := newTestExecutor(t, s, "1h")
+
+	e.Run()
+	assert.Nil(t, e.OverrideFrequency("10ms", time.Now().Add(55*time.Millisecond)))
+	time.Sleep(100 * time.Millisecond)
+	e.Stop()
+	count := recorder.count(e)
+	assert.True(t, count >= 3 && count <= 5, "expected about 5 runs until the override expires, got %d", count)
+	assert.True(t, e.Status().NextRun == nil)
+}
+
+func TestExecutorStatus(t *testing.T) {
+	s := newScheduler(1, 0, nil)
+	e, err := newExecutor(s, "device", contract.AutoEvent{Frequency: "1s", OnChange: true, Resource: "resource"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	status := e.Status()
+	assert.Equal(t, ExecutorStatus{Device: "device", Resource: "resource", Frequency: "1s", OnChange: true}, status)
+
+	at := time.Now()
+	s.record(e, at, ResultError, "read failure")
+	s.record(e, at.Add(time.Second), ResultSent, "")
+	status = e.Status()
+	if assert.NotNil(t, status.LastRun) {
+		assert.Equal(t, at.Add(time.Second), *status.LastRun)
+	}
+	assert.Equal(t, ResultSent, status.LastResult)
+	assert.Equal(t, "read failure", status.LastError)
+	assert.Equal(t, uint64(1), status.ErrorCount)
+}
+
+func TestSchedulerSendStopped(t *testing.T) {
+	s := newScheduler(1, 0, nil)
+	sending := make(chan bool)
+	release := make(chan bool)
+	var sent int32
+	s.sendEvent = func(evt *dsModels.Event) {
+		sending <- true
+		<-release
+		atomic.AddInt32(&sent, 1)
+	}
+	e := newTestExecutor(t, s, "1h")
+
+	result := make(chan bool)
+	go func() { result <- s.send(context.Background(), e, &dsModels.Event{}) }()
+	<-sending
+
+	// Stop doesn't wait for the event being sent
+	stopped := make(chan bool)
+	go func() {
+		e.Stop()
+		stopped <- true
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop waits for the event being sent")
+	}
+	release <- true
+	assert.True(t, <-result)
+
+	// no event is sent once Stop returned
+	assert.False(t, s.send(context.Background(), e, &dsModels.Event{}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sent))
+
+	// nor once the context of the run is done
+	e = newTestExecutor(t, s, "1h")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, s.send(ctx, e, &dsModels.Event{}))
+}
+
+func TestSchedulerStop(t *testing.T) {
+	runs := make(chan context.Context, 16)
+	release := make(chan bool)
+	defer close(release)
+	s := newScheduler(1, 0, func(ctx context.Context, e *executor) {
+		runs <- ctx
+		<-release
+	})
+	e := newTestExecutor(t, s, "10ms")
+	e.Run()
+
+	var ctx context.Context
+	select {
+	case ctx = <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("the executor didn't run")
+	}
+
+	// stop cancels the run in progress without waiting for it
+	s.stop()
+	assert.Error(t, ctx.Err())
+	release <- true
+
+	// no run starts once the scheduler is stopped
+	select {
+	case <-runs:
+		t.Error("the executor ran after the scheduler was stopped")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.stop()
+
+	// the executor runs again once the scheduler is started
+	s.start()
+	select {
+	case ctx = <-runs:
+		assert.NoError(t, ctx.Err())
+	case <-time.After(time.Second):
+		t.Fatal("the executor didn't run after the scheduler was started")
+	}
+	e.Stop()
+	s.stop()
+}
@@ -47,3 +47,15 @@ func NewBadRequestError(msg string, err error) AppError {
 func NewLockedError(msg string, err error) AppError {
 	return appError{err: err, msg: msg, code: http.StatusLocked}
 }
+
+func NewConflictError(msg string, err error) AppError {
+	return appError{err: err, msg: msg, code: http.StatusConflict}
+}
+
+func NewServiceUnavailableError(msg string, err error) AppError {
+	return appError{err: err, msg: msg, code: http.StatusServiceUnavailable}
+}
+
+func NewTimeoutError(msg string, err error) AppError {
+	return appError{err: err, msg: msg, code: http.StatusGatewayTimeout}
+}
@@ -38,11 +38,16 @@ const (
 	APIIdCommandRoute       = clients.ApiDeviceRoute + "/{id}/{command}"
 	APINameCommandRoute     = clients.ApiDeviceRoute + "/name/{name}/{command}"
 	APIDiscoveryRoute       = clients.ApiBase + "/discovery"
-	APITransformRoute       = clients.ApiBase + "/debug/transformData/{transformData}"
+	APITransformRoute       = clients.ApiBase + "/debug/transformData/{resource}"
+	APIAutoEventRoute       = clients.ApiBase + "/autoevent"
+	APIAutoEventNameRoute   = clients.ApiBase + "/autoevent/name/{name}"
+	APIAutoEventActionRoute = clients.ApiBase + "/autoevent/name/{name}/{resource}/{action}"
 
 	IdVar        string = "id"
 	NameVar      string = "name"
 	CommandVar   string = "command"
+	ResourceVar  string = "resource"
+	ActionVar    string = "action"
 	GetCmdMethod string = "get"
 	SetCmdMethod string = "set"
 
@@ -24,7 +24,9 @@ var (
 	OverwriteConfig        bool
 	ServiceLocked          bool
 	Driver                 dsModels.ProtocolDriver
+	Discovery              dsModels.ProtocolDiscovery
 	EventClient            coredata.EventClient
+	Publisher              EventPublisher
 	AddressableClient      metadata.AddressableClient
 	DeviceClient           metadata.DeviceClient
 	DeviceServiceClient    metadata.DeviceServiceClient
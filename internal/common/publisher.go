@@ -0,0 +1,28 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"errors"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// EventPublisher sends the events of the Device Service to their destination,
+// e.g. Core Data or a message bus.
+type EventPublisher interface {
+	// Publish sends the event. event.EncodedEvent holds the encoded event, and
+	// ctx carries its content type and the correlation id.
+	Publish(ctx context.Context, event *dsModels.Event) error
+	// Close releases the resources held by the EventPublisher.
+	Close() error
+}
+
+// ErrEventQueued is returned by Publish when the event couldn't be published now,
+// and it's stored to be published later.
+var ErrEventQueued = errors.New("event is queued to be published later")
@@ -40,8 +40,12 @@ type ServiceInfo struct {
 	Timeout int
 	// EnableAsyncReadings to determine whether the Device Service would deal with the asynchronous readings
 	EnableAsyncReadings bool
-	// AsyncBufferSize defines the size of asynchronous channel
+	// AsyncBufferSize defines the size of asynchronous channel, and the size of the
+	// buffer of each asynchronous reading worker.
 	AsyncBufferSize int
+	// AsyncWorkers is the number of workers processing the asynchronous readings,
+	// the readings of a Device are always processed in order by the same worker.
+	AsyncWorkers int
 }
 
 type RegistryService struct {
@@ -67,11 +71,27 @@ type DeviceInfo struct {
 	// DataTransform specifies whether or not the DS perform transformations
 	// specified by valuedescriptor on a actuation or query command.
 	DataTransform bool
+	// AssertionPolicy specifies how the readings failing the assertion of their
+	// DeviceResource are handled, "disable" (the default) marks the device DISABLED,
+	// "warn" only logs the failure and "drop" drops the reading. A DeviceResource
+	// can override it with the assertionPolicy attribute.
+	AssertionPolicy string
+	// AssertionRecoveryReads is the number of consecutive passing assertions of
+	// each failed DeviceResource after which a device disabled by a failed
+	// assertion is enabled again, 0 means the device stays disabled until it is
+	// enabled by hand.
+	AssertionRecoveryReads int
 	// InitCmd specifies a device resource command which is automatically
-	// generated whenever a new device is added to the DS.
+	// generated whenever a new device is added to the DS, and for the devices
+	// which exist when the DS starts.
 	InitCmd string
-	// InitCmdArgs specify arguments to be used when building the InitCmd.
+	// InitCmdArgs specify arguments to be used when building the InitCmd, the
+	// InitCmd is a PUT with InitCmdArgs as the JSON body if they are set,
+	// otherwise a GET.
 	InitCmdArgs string
+	// DisableOnInitCmdFailure specifies whether a device is marked DISABLED
+	// when its InitCmd fails.
+	DisableOnInitCmdFailure bool
 	// MaxCmdOps defines the maximum number of resource operations that
 	// can be sent to a Driver in a single command.
 	MaxCmdOps int
@@ -79,10 +99,16 @@ type DeviceInfo struct {
 	// result (including the valuedescriptor name) that can be returned
 	// by a Driver.
 	MaxCmdValueLen int
-	// InitCmd specifies a device resource command which is automatically
-	// generated whenever a new device is removed from the DS.
+	// MaxCmdValueLenPolicy specifies how the String and Binary results longer than
+	// MaxCmdValueLen are handled, "off" (the default) leaves them as they are,
+	// "reject" fails the command and "truncate" cuts the value to MaxCmdValueLen
+	// bytes.
+	MaxCmdValueLenPolicy string
+	// RemoveCmd specifies a device resource command which is automatically
+	// generated whenever a device is removed from the DS.
 	RemoveCmd string
-	// RemoveCmdArgs specify arguments to be used when building the RemoveCmd.
+	// RemoveCmdArgs specify arguments to be used when building the RemoveCmd,
+	// in the same way as InitCmdArgs.
 	RemoveCmdArgs string
 	// ProfilesDir specifies a directory which contains deviceprofile
 	// files which should be imported on startup.
@@ -90,6 +116,100 @@ type DeviceInfo struct {
 	// UpdateLastConnected specifies whether to update device's LastConnected
 	// timestamp in metadata.
 	UpdateLastConnected bool
+	// Discovery contains the configuration of the periodic device discovery
+	Discovery DiscoveryInfo
+	// AutoEvent contains the settings of the scheduler of the AutoEvents
+	AutoEvent AutoEventInfo
+}
+
+// AutoEventInfo is a struct which contains the settings of the scheduler which
+// runs the AutoEvents of all the devices.
+type AutoEventInfo struct {
+	// Workers is the number of AutoEvents read concurrently, 4 if it's not set.
+	Workers int
+	// Jitter is the maximum random delay added to each run of an AutoEvent, e.g.
+	// "100ms", to spread the reads of the AutoEvents sharing a Frequency.
+	Jitter string
+	// ChangedReadingsOnly specifies whether the events of the OnChange AutoEvents
+	// contain only the changed readings rather than all the readings of the
+	// command.
+	ChangedReadingsOnly bool
+	// MaxSilence is the time after which an OnChange AutoEvent sends an event
+	// with all the readings even if none changed, e.g. "15m". The events are
+	// only sent on change if it's not set.
+	MaxSilence string
+}
+
+// DiscoveryInfo is a struct which contains the periodic device discovery settings.
+type DiscoveryInfo struct {
+	// Enabled specifies whether the device discovery is triggered periodically
+	Enabled bool
+	// Interval is the time duration between two discovery runs, e.g. "30s" or "10m"
+	Interval string
+}
+
+// EventPublisherInfo is a struct which contains the settings of the EventPublisher
+// the events are sent through.
+type EventPublisherInfo struct {
+	// Type of the EventPublisher, "coredata" (the default) posts the events to Core Data
+	// via REST and "mqtt" publishes them to a MQTT broker.
+	Type string
+	// Host is the hostname or IP address of the message bus broker.
+	Host string
+	// Port is the port of the message bus broker.
+	Port int
+	// Protocol indicates the protocol to use when accessing the broker, i.e. tcp, tls, ws or wss
+	Protocol string
+	// Topic is the template of the topic an event is published to, the {device} and
+	// {profile} placeholders are replaced by the name of the Device and its DeviceProfile.
+	Topic string
+	// Optional contains the settings specific to the Type, e.g. ClientId, Username,
+	// Password, Qos, Retained, KeepAlive and Timeout of the MQTT client, and its
+	// CaFile, CertFile, KeyFile and SkipCertVerify TLS settings.
+	Optional map[string]string
+	// StoreAndForward contains the settings of the on-disk queue of the events
+	// which failed to be published.
+	StoreAndForward StoreAndForwardInfo
+	// Batch contains the settings of grouping the events into batches, which
+	// only the "mqtt" Type supports.
+	Batch BatchInfo
+	// MaxConcurrentSends is the maximum number of events being sent concurrently,
+	// zero means no limit.
+	MaxConcurrentSends int
+}
+
+// BatchInfo is a struct which contains the settings of grouping the events into
+// batches, a batch is sent when it reaches MaxBatchSize or MaxBatchWait elapsed.
+type BatchInfo struct {
+	// Enabled specifies whether the events are sent in batches
+	Enabled bool
+	// MaxBatchSize is the maximum number of events in a batch.
+	MaxBatchSize int
+	// MaxBatchWait is the maximum time duration an event waits for its batch to be sent, e.g. "100ms".
+	MaxBatchWait string
+}
+
+// StoreAndForwardInfo is a struct which contains the settings of the on-disk queue
+// the events are stored in until they are published successfully.
+type StoreAndForwardInfo struct {
+	// Enabled specifies whether the events which failed to be published are stored and retried
+	Enabled bool
+	// Dir is the directory the queued events are stored in.
+	Dir string
+	// MaxEvents is the maximum number of queued events, the oldest event is dropped
+	// when the limit is exceeded. Zero means no limit.
+	MaxEvents int
+	// MaxBytes is the maximum total size of the queued events, the oldest events are
+	// dropped when the limit is exceeded. Zero means no limit.
+	MaxBytes int64
+	// MaxAge is the time duration after which a queued event is dropped, e.g. "24h".
+	// An empty string means no limit.
+	MaxAge string
+	// RetryInterval is the time duration to wait before the first retry, e.g. "1s".
+	// It is doubled after each failed retry.
+	RetryInterval string
+	// MaxRetryInterval is the upper bound of the time duration between two retries, e.g. "5m".
+	MaxRetryInterval string
 }
 
 // LoggingInfo is a struct which contains logging specific configuration settings.
@@ -114,6 +234,8 @@ type Config struct {
 	Device DeviceInfo
 	// Logging contains logging-specific configuration settings.
 	Logging LoggingInfo
+	// EventPublisher contains the settings of the publisher the events are sent through.
+	EventPublisher EventPublisherInfo
 	// DeviceList is the list of pre-define Devices
 	DeviceList []DeviceConfig `consul:"-"`
 	// Driver is a string map contains customized configuration for the protocol driver implemented based on Device SDK
@@ -164,4 +286,36 @@ type Telemetry struct {
 	Mallocs,
 	Frees,
 	LiveObjects uint64
+	// EventQueue contains the metrics of the store-and-forward queue of the events
+	EventQueue EventQueueMetrics
+	// AsyncReadings contains the metrics of the processing of the asynchronous readings
+	AsyncReadings AsyncMetrics
+}
+
+// AsyncMetrics provides the backpressure metrics of the asynchronous readings.
+type AsyncMetrics struct {
+	// ChannelDepth is the number of AsyncValues waiting in the channel the Driver pushes to
+	ChannelDepth uint64
+	// ChannelCapacity is the AsyncBufferSize of the channel
+	ChannelCapacity uint64
+	// Queued is the number of AsyncValues waiting in the buffers of the workers
+	Queued uint64
+	// Blocked is the number of AsyncValues which waited for room in the buffer of their worker
+	Blocked uint64
+	// Processed is the number of AsyncValues processed by the workers
+	Processed uint64
+}
+
+// EventQueueMetrics provides the metrics of the store-and-forward queue of the events.
+type EventQueueMetrics struct {
+	// Depth is the number of queued events
+	Depth uint64
+	// Bytes is the total size of the queued events
+	Bytes uint64
+	// DroppedFull is the number of events dropped because the queue exceeded its size limits
+	DroppedFull uint64
+	// DroppedExpired is the number of events dropped because they exceeded MaxAge
+	DroppedExpired uint64
+	// DroppedRejected is the number of queued events rejected by their destination
+	DroppedRejected uint64
 }
@@ -26,6 +26,8 @@ import (
 var (
 	previousOrigin int64
 	originMutex    sync.Mutex
+	sendEventSem   chan struct{}
+	sendEventOnce  sync.Once
 )
 
 func BuildAddr(host string, port string) string {
@@ -57,6 +59,48 @@ func CommandValueToReading(cv *dsModels.CommandValue, devName string, encoding s
 	return reading
 }
 
+// MediaType returns the media type of a Binary CommandValue, which is the MediaType
+// set by the Driver or the MediaType of the DeviceResource.
+func MediaType(cv *dsModels.CommandValue, dr contract.DeviceResource) string {
+	if cv.Type != dsModels.Binary {
+		return ""
+	} else if cv.MediaType != "" {
+		return cv.MediaType
+	}
+	return dr.Properties.Value.MediaType
+}
+
+// SendEventAsync calls SendEvent in a new goroutine. It blocks while the number of
+// running SendEvent goroutines has reached [EventPublisher] MaxConcurrentSends.
+func SendEventAsync(event *dsModels.Event) {
+	sendEventOnce.Do(func() {
+		if CurrentConfig.EventPublisher.MaxConcurrentSends > 0 {
+			sendEventSem = make(chan struct{}, CurrentConfig.EventPublisher.MaxConcurrentSends)
+		}
+	})
+
+	if sendEventSem == nil {
+		go SendEvent(event)
+		return
+	}
+
+	sendEventSem <- struct{}{}
+	go func() {
+		defer func() { <-sendEventSem }()
+		SendEvent(event)
+	}()
+}
+
+// EncodeEvent encodes the event with the EventClient, unless the quality of
+// some readings isn't good or some binary readings have a media type, in which
+// case the readings are encoded with their quality and media type.
+func EncodeEvent(event *dsModels.Event) ([]byte, error) {
+	if event.HasReadingDetails() {
+		return event.EncodeWithDetails()
+	}
+	return EventClient.MarshalEvent(event.Event)
+}
+
 func SendEvent(event *dsModels.Event) {
 	correlation := uuid.New().String()
 	ctx := context.WithValue(context.Background(), CorrelationHeader, correlation)
@@ -68,19 +112,25 @@ func SendEvent(event *dsModels.Event) {
 	// Call MarshalEvent to encode as byte array whether event contains binary or JSON readings
 	var err error
 	if len(event.EncodedEvent) <= 0 {
-		event.EncodedEvent, err = EventClient.MarshalEvent(event.Event)
+		event.EncodedEvent, err = EncodeEvent(event)
 		if err != nil {
 			LoggingClient.Error("SendEvent: Error encoding event", "device", event.Device, clients.CorrelationHeader, correlation, "error", err)
-		} else {
-			LoggingClient.Debug("SendEvent: EventClient.MarshalEvent encoded event", clients.CorrelationHeader, correlation)
+			return
 		}
+		LoggingClient.Debug("SendEvent: EventClient.MarshalEvent encoded event", clients.CorrelationHeader, correlation)
 	} else {
 		LoggingClient.Debug("SendEvent: EventClient.MarshalEvent passed through encoded event", clients.CorrelationHeader, correlation)
 	}
-	// Call AddBytes to post event to core data
-	responseBody, errPost := EventClient.AddBytes(event.EncodedEvent, ctx)
-	if errPost != nil {
-		LoggingClient.Error("SendEvent Failed to push event", "device", event.Device, "response", responseBody, "error", errPost)
+
+	if Publisher == nil {
+		LoggingClient.Error("SendEvent: EventPublisher is not initialized", "device", event.Device, clients.CorrelationHeader, correlation)
+		return
+	}
+	errPublish := Publisher.Publish(ctx, event)
+	if errPublish == ErrEventQueued {
+		LoggingClient.Warn("SendEvent: Queued event to be published later", "device", event.Device, clients.CorrelationHeader, correlation)
+	} else if errPublish != nil {
+		LoggingClient.Error("SendEvent Failed to publish event", "device", event.Device, clients.CorrelationHeader, correlation, "error", errPublish)
 	} else {
 		if CurrentConfig.Device.UpdateLastConnected {
 			t := time.Now().UnixNano() / int64(time.Millisecond)
@@ -90,8 +140,8 @@ func SendEvent(event *dsModels.Event) {
 			}
 		}
 
-		LoggingClient.Info("SendEvent: Pushed event to core data", clients.ContentType, clients.FromContext(clients.ContentType, ctx), clients.CorrelationHeader, correlation)
-		LoggingClient.Trace("SendEvent: Pushed this event to core data", clients.ContentType, clients.FromContext(clients.ContentType, ctx), clients.CorrelationHeader, correlation, "event", event)
+		LoggingClient.Info("SendEvent: Published event", clients.ContentType, clients.FromContext(clients.ContentType, ctx), clients.CorrelationHeader, correlation)
+		LoggingClient.Trace("SendEvent: Published this event", clients.ContentType, clients.FromContext(clients.ContentType, ctx), clients.CorrelationHeader, correlation, "event", event)
 	}
 }
 
@@ -8,8 +8,15 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/ugorji/go/codec"
 )
 
 func TestBuildAddr(t *testing.T) {
@@ -112,3 +119,86 @@ func TestFilterQueryParams(t *testing.T) {
 		}
 	}
 }
+
+// blockingPublisher blocks Publish until release is closed.
+type blockingPublisher struct {
+	started chan bool
+	release chan bool
+}
+
+func (p blockingPublisher) Publish(ctx context.Context, event *dsModels.Event) error {
+	p.started <- true
+	<-p.release
+	return nil
+}
+
+func (p blockingPublisher) Close() error {
+	return nil
+}
+
+func TestSendEventAsyncMaxConcurrentSends(t *testing.T) {
+	LoggingClient = logger.NewMockClient()
+	CurrentConfig = &Config{EventPublisher: EventPublisherInfo{MaxConcurrentSends: 2}}
+	p := blockingPublisher{started: make(chan bool, 3), release: make(chan bool)}
+	Publisher = p
+	defer func() { Publisher = nil }()
+
+	returned := make(chan bool)
+	go func() {
+		for i := 0; i < 3; i++ {
+			SendEventAsync(&dsModels.Event{EncodedEvent: []byte("{}")})
+		}
+		returned <- true
+	}()
+
+	<-p.started
+	<-p.started
+	select {
+	case <-returned:
+		t.Fatal("SendEventAsync is supposed to block while MaxConcurrentSends events are being sent")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(p.release)
+	<-returned
+	<-p.started
+}
+
+func TestMediaType(t *testing.T) {
+	dr := contract.DeviceResource{Properties: contract.ProfileProperty{Value: contract.PropertyValue{MediaType: "image/jpeg"}}}
+	binaryValue, _ := dsModels.NewBinaryValue("Image", 0, []byte{0})
+	if mediaType := MediaType(binaryValue, dr); mediaType != "image/jpeg" {
+		t.Errorf("expected the MediaType of the DeviceResource, got %s", mediaType)
+	}
+	binaryValue.MediaType = "image/png"
+	if mediaType := MediaType(binaryValue, dr); mediaType != "image/png" {
+		t.Errorf("expected the MediaType of the CommandValue, got %s", mediaType)
+	}
+	if mediaType := MediaType(dsModels.NewStringValue("Image", 0, ""), dr); mediaType != "" {
+		t.Errorf("expected no MediaType for a String value, got %s", mediaType)
+	}
+}
+
+func TestEncodeEventMediaType(t *testing.T) {
+	image := contract.Reading{Name: "Image", BinaryValue: []byte{0x89, 'P', 'N', 'G'}}
+	event := &dsModels.Event{
+		Event:      contract.Event{Device: "device", Readings: []contract.Reading{image}},
+		MediaTypes: map[string]string{"Image": "image/png"},
+	}
+	data, err := EncodeEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Readings []struct {
+			MediaType string `codec:"mediaType"`
+		} `codec:"readings"`
+	}
+	if err = codec.NewDecoderBytes(data, &codec.CborHandle{}).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Readings) != 1 || decoded.Readings[0].MediaType != "image/png" {
+		t.Errorf("expected the media type in the encoded event, got %+v", decoded)
+	}
+}
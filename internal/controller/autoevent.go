@@ -0,0 +1,113 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/autoevent"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/gorilla/mux"
+)
+
+// The actions of the AutoEvent management API, pause, resume and trigger are
+// POSTed, the frequency override is PUT and DELETEd.
+const (
+	autoEventPause     = "pause"
+	autoEventResume    = "resume"
+	autoEventTrigger   = "trigger"
+	autoEventFrequency = "frequency"
+)
+
+// frequencyOverride is the body of a frequency override request.
+type frequencyOverride struct {
+	Frequency string `json:"frequency"`
+	// Duration is how long the override lasts, e.g. "1h", until the
+	// AutoEvents of the Device are restarted if it's empty.
+	Duration string `json:"duration,omitempty"`
+}
+
+func autoEventStatusFunc(w http.ResponseWriter, req *http.Request) {
+	if checkServiceLocked(w, req) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	statuses, appErr := autoevent.GetManager().Status(vars[common.NameVar])
+	if appErr != nil {
+		http.Error(w, appErr.Message(), appErr.Code())
+		return
+	}
+	encode(statuses, w)
+}
+
+func autoEventActionFunc(w http.ResponseWriter, req *http.Request) {
+	if checkServiceLocked(w, req) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	deviceName, resource, action := vars[common.NameVar], vars[common.ResourceVar], vars[common.ActionVar]
+	common.LoggingClient.Info(fmt.Sprintf("service: autoevent request: %s %s of device: %s, resource: %s", req.Method, action, deviceName, resource))
+
+	m := autoevent.GetManager()
+	var appErr common.AppError
+	status := http.StatusOK
+	switch {
+	case action == autoEventPause && req.Method == http.MethodPost:
+		appErr = m.Pause(deviceName, resource)
+	case action == autoEventResume && req.Method == http.MethodPost:
+		appErr = m.Resume(deviceName, resource)
+	case action == autoEventTrigger && req.Method == http.MethodPost:
+		// the AutoEvent runs on a worker of the scheduler
+		appErr = m.Trigger(deviceName, resource)
+		status = http.StatusAccepted
+	case action == autoEventFrequency && req.Method == http.MethodPut:
+		appErr = overrideFrequency(req, m, deviceName, resource)
+	case action == autoEventFrequency && req.Method == http.MethodDelete:
+		appErr = m.OverrideFrequency(deviceName, resource, "", 0)
+	case action == autoEventPause || action == autoEventResume || action == autoEventTrigger || action == autoEventFrequency:
+		msg := fmt.Sprintf("%s isn't supported by the AutoEvent action %s", req.Method, action)
+		http.Error(w, msg, http.StatusMethodNotAllowed) // status=405
+		return
+	default:
+		msg := fmt.Sprintf("unknown AutoEvent action %s", action)
+		http.Error(w, msg, http.StatusNotFound) // status=404
+		return
+	}
+
+	if appErr != nil {
+		common.LoggingClient.Error(appErr.Message())
+		http.Error(w, appErr.Message(), appErr.Code())
+		return
+	}
+	w.WriteHeader(status)
+	io.WriteString(w, statusOK)
+}
+
+func overrideFrequency(req *http.Request, m autoevent.Manager, deviceName string, resource string) common.AppError {
+	defer req.Body.Close()
+	var override frequencyOverride
+	if err := json.NewDecoder(req.Body).Decode(&override); err != nil {
+		return common.NewBadRequestError(fmt.Sprintf("invalid frequency override request: %v", err), err)
+	} else if override.Frequency == "" {
+		return common.NewBadRequestError("the frequency of the override is required, DELETE the override to remove it", nil)
+	}
+
+	var duration time.Duration
+	if override.Duration != "" {
+		var err error
+		if duration, err = time.ParseDuration(override.Duration); err != nil || duration <= 0 {
+			return common.NewBadRequestError(fmt.Sprintf("the duration %s of the frequency override should be a positive duration", override.Duration), err)
+		}
+	}
+	return m.OverrideFrequency(deviceName, resource, override.Frequency, duration)
+}
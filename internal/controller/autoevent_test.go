@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoEventAction(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		action string
+		body   string
+		code   int
+	}{
+		{"Unknown action", http.MethodPost, "stop", "", http.StatusNotFound},
+		{"Invalid method", http.MethodGet, "pause", "", http.StatusMethodNotAllowed},
+		{"Unsupported method", http.MethodPut, "pause", "", http.StatusMethodNotAllowed},
+		{"No AutoEvent", http.MethodPost, "trigger", "", http.StatusNotFound},
+		{"Invalid body", http.MethodPut, "frequency", "5s", http.StatusBadRequest},
+		{"No frequency", http.MethodPut, "frequency", `{"duration":"1h"}`, http.StatusBadRequest},
+		{"Invalid duration", http.MethodPut, "frequency", `{"frequency":"5s","duration":"-1h"}`, http.StatusBadRequest},
+		{"No AutoEvent override", http.MethodPut, "frequency", `{"frequency":"5s","duration":"1h"}`, http.StatusNotFound},
+	}
+
+	common.ServiceLocked = false
+	controller := NewRestController()
+	controller.InitRestRoutes()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := strings.NewReplacer("{name}", "device", "{resource}", "resource", "{action}", tt.action).Replace(common.APIAutoEventActionRoute)
+			req := httptest.NewRequest(tt.method, route, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			controller.router.ServeHTTP(rr, req)
+			assert.Equal(t, tt.code, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+func TestAutoEventStatus(t *testing.T) {
+	common.ServiceLocked = false
+	controller := NewRestController()
+	controller.InitRestRoutes()
+
+	req := httptest.NewRequest(http.MethodGet, common.APIAutoEventRoute, nil)
+	rr := httptest.NewRecorder()
+	controller.router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, clients.ContentTypeJSON, rr.Header().Get(clients.ContentType))
+	assert.Equal(t, "[]\n", rr.Body.String())
+}
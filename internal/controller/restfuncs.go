@@ -1,7 +1,7 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
 // Copyright (C) 2017-2018 Canonical Ltd
-// Copyright (C) 2018-2019 IOTech Ltd
+// Copyright (C) 2018-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -15,9 +15,11 @@ import (
 	"net/http"
 	"runtime"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/async"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	"github.com/edgexfoundry/device-sdk-go/internal/handler"
 	"github.com/edgexfoundry/device-sdk-go/internal/handler/callback"
+	"github.com/edgexfoundry/device-sdk-go/internal/publisher"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
 	"github.com/gorilla/mux"
@@ -50,10 +52,21 @@ func discoveryFunc(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if common.Discovery == nil {
+		msg := fmt.Sprintf("%s doesn't support dynamic device discovery", common.ServiceName)
+		common.LoggingClient.Error(msg)
+		http.Error(w, msg, http.StatusNotImplemented) // status=501
+		return
+	}
+
 	vars := mux.Vars(req)
-	go handler.DiscoveryHandler(vars)
-	io.WriteString(w, statusOK)
+	appErr := handler.DiscoveryHandler(vars)
+	if appErr != nil {
+		http.Error(w, appErr.Message(), appErr.Code())
+		return
+	}
 	w.WriteHeader(http.StatusAccepted)
+	io.WriteString(w, statusOK)
 }
 
 func transformFunc(w http.ResponseWriter, req *http.Request) {
@@ -62,12 +75,13 @@ func transformFunc(w http.ResponseWriter, req *http.Request) {
 	}
 
 	vars := mux.Vars(req)
-	_, appErr := handler.TransformHandler(vars)
+	result, appErr := handler.TransformHandler(vars, req.URL.RawQuery)
 	if appErr != nil {
-		w.WriteHeader(appErr.Code())
-	} else {
-		io.WriteString(w, statusOK)
+		http.Error(w, appErr.Message(), appErr.Code())
+		return
 	}
+	w.Header().Set(clients.ContentType, clients.ContentTypeJSON)
+	json.NewEncoder(w).Encode(result)
 }
 
 func callbackFunc(w http.ResponseWriter, req *http.Request) {
@@ -105,17 +119,22 @@ func commandFunc(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	event, appErr := handler.CommandHandler(vars, body, req.Method, req.URL.RawQuery)
+	// the handler accepts raw binary request bodies according to the Content-Type
+	event, appErr := handler.CommandHandler(req.Context(), vars, body, req.Header.Get(clients.ContentType), req.Method, req.URL.RawQuery)
 
 	if appErr != nil {
-		http.Error(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
+		writeCommandError(w, fmt.Sprintf("%s %s", appErr.Message(), req.URL.Path), appErr.Code())
 	} else if event != nil {
-		if event.HasBinaryValue() {
+		if reading, mediaType, ok := event.SingleBinaryReading(); ok && mediaType != "" {
+			// the binary value is returned as it is, e.g. an image
+			w.Header().Set(clients.ContentType, mediaType)
+			w.Write(reading.BinaryValue)
+		} else if event.HasBinaryValue() {
 			// TODO: Add conditional toggle in case caller of command does not require this response.
 			// Encode response as application/CBOR.
 			if len(event.EncodedEvent) <= 0 {
 				var err error
-				event.EncodedEvent, err = common.EventClient.MarshalEvent(event.Event)
+				event.EncodedEvent, err = common.EncodeEvent(event)
 				if err != nil {
 					common.LoggingClient.Error("DeviceCommand: Error encoding event", "device", event.Device, "error", err)
 				} else {
@@ -127,12 +146,21 @@ func commandFunc(w http.ResponseWriter, req *http.Request) {
 			// TODO: Resolve why this header is not included in response from Core-Command to originating caller (while the written body is).
 			w.Header().Set(clients.ContentType, clients.ContentTypeCBOR)
 			w.Write(event.EncodedEvent)
+		} else if event.HasDegradedReadings() {
+			// the readings are returned with their quality
+			var err error
+			event.EncodedEvent, err = common.EncodeEvent(event)
+			if err != nil {
+				common.LoggingClient.Error("DeviceCommand: Error encoding event", "device", event.Device, "error", err)
+			}
+			w.Header().Set(clients.ContentType, clients.ContentTypeJSON)
+			w.Write(event.EncodedEvent)
 		} else {
 			w.Header().Set(clients.ContentType, clients.ContentTypeJSON)
 			json.NewEncoder(w).Encode(event)
 		}
 		// push to Core Data
-		go common.SendEvent(event)
+		common.SendEventAsync(event)
 	}
 }
 
@@ -149,14 +177,14 @@ func commandAllFunc(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	events, appErr := handler.CommandAllHandler(vars[common.CommandVar], body, req.Method, req.URL.RawQuery)
+	events, appErr := handler.CommandAllHandler(req.Context(), vars[common.CommandVar], body, req.Header.Get(clients.ContentType), req.Method, req.URL.RawQuery)
 	if appErr != nil {
-		http.Error(w, appErr.Message(), appErr.Code())
+		writeCommandError(w, appErr.Message(), appErr.Code())
 	} else if len(events) > 0 {
 		// push to Core Data
 		for _, event := range events {
 			if event != nil {
-				go common.SendEvent(event)
+				common.SendEventAsync(event)
 			}
 		}
 		w.Header().Set(clients.ContentType, clients.ContentTypeJSON)
@@ -164,6 +192,16 @@ func commandAllFunc(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// writeCommandError writes the error of a command, the timeout errors are
+// written as structured error responses.
+func writeCommandError(w http.ResponseWriter, msg string, code int) {
+	if code == http.StatusGatewayTimeout || code == http.StatusServiceUnavailable {
+		writeErrorResponse(w, msg, code)
+	} else {
+		http.Error(w, msg, code)
+	}
+}
+
 func checkServiceLocked(w http.ResponseWriter, req *http.Request) bool {
 	if common.ServiceLocked {
 		msg := fmt.Sprintf("%s is locked; %s %s", common.ServiceName, req.Method, req.URL)
@@ -176,8 +214,14 @@ func checkServiceLocked(w http.ResponseWriter, req *http.Request) bool {
 
 func readBodyAsString(w http.ResponseWriter, req *http.Request) (string, bool) {
 	defer req.Body.Close()
-	body, err := ioutil.ReadAll(req.Body)
-	if err != nil {
+	maxLen := handler.MaxCommandBodyLen(req.Header.Get(clients.ContentType))
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxLen))
+	if err != nil && int64(len(body)) >= maxLen {
+		msg := fmt.Sprintf("request body exceeds %d bytes; %s %s", maxLen, req.Method, req.URL)
+		common.LoggingClient.Error(msg)
+		http.Error(w, msg, http.StatusRequestEntityTooLarge) // status=413
+		return "", false
+	} else if err != nil {
 		msg := fmt.Sprintf("error reading request body for: %s %s", req.Method, req.URL)
 		common.LoggingClient.Error(msg)
 		return "", false
@@ -213,6 +257,9 @@ func metricsHandler(w http.ResponseWriter, _ *http.Request) {
 	// Live objects = Mallocs - Frees
 	t.LiveObjects = t.Mallocs - t.Frees
 
+	t.EventQueue = publisher.QueueMetrics()
+	t.AsyncReadings = async.Metrics()
+
 	encode(t, w)
 
 	return
@@ -16,6 +16,7 @@ import (
 	"testing"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
 	"github.com/edgexfoundry/device-sdk-go/internal/mock"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
@@ -123,3 +124,64 @@ func TestCommandNoDevice(t *testing.T) {
 		t.Errorf("No Device: handler returned wrong body:\nexpected: %s\ngot:      %s", expected, body)
 	}
 }
+
+// TestCommandBodyTooLarge tests the command REST call whose body exceeds the
+// maximum length of its Content-Type.
+func TestCommandBodyTooLarge(t *testing.T) {
+	lc := logger.NewClient("command_test", false, "./command_test.log", "DEBUG")
+	common.LoggingClient = lc
+	common.ServiceLocked = false
+	common.CurrentConfig = &common.Config{Device: common.DeviceInfo{MaxCmdOps: 128, MaxCmdValueLen: 256}}
+	defer func() {
+		common.CurrentConfig = &common.Config{}
+	}()
+	common.DeviceClient = &mock.DeviceClientMock{}
+	common.ValueDescriptorClient = &mock.ValueDescriptorMock{}
+	common.ProvisionWatcherClient = &mock.ProvisionWatcherClientMock{}
+	controller := NewRestController()
+	controller.InitRestRoutes()
+
+	var tests = []struct {
+		name        string
+		contentType string
+		bodyLen     int64
+		code        int
+	}{
+		{"Binary", handler.ContentTypeOctetStream, handler.MaxCommandBodyLen(handler.ContentTypeOctetStream), http.StatusNotFound},
+		{"BinaryTooLarge", handler.ContentTypeOctetStream, handler.MaxCommandBodyLen(handler.ContentTypeOctetStream) + 1, http.StatusRequestEntityTooLarge},
+		{"JSONTooLarge", clients.ContentTypeJSON, handler.MaxCommandBodyLen(clients.ContentTypeJSON) + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := fmt.Sprintf("%s/%s/%s", clients.ApiDeviceRoute, badDeviceId, testCmd)
+			req := httptest.NewRequest(http.MethodPut, url, bytes.NewReader(make([]byte, tt.bodyLen)))
+			req.Header.Set(clients.ContentType, tt.contentType)
+
+			rr := httptest.NewRecorder()
+			controller.router.ServeHTTP(rr, req)
+			if status := rr.Code; status != tt.code {
+				t.Errorf("BodyTooLarge: handler returned wrong status code: got %v want %v", status, tt.code)
+			}
+		})
+	}
+}
+
+// TestDiscoveryNotSupported tests the discovery REST call when the Driver doesn't
+// implement the ProtocolDiscovery interface.
+func TestDiscoveryNotSupported(t *testing.T) {
+	lc := logger.NewClient("discovery_test", false, "./discovery_test.log", "DEBUG")
+	common.LoggingClient = lc
+	common.ServiceLocked = false
+	common.Discovery = nil
+	controller := NewRestController()
+	controller.InitRestRoutes()
+
+	req := httptest.NewRequest(http.MethodPost, common.APIDiscoveryRoute, nil)
+	rr := httptest.NewRecorder()
+	controller.router.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotImplemented {
+		t.Errorf("DiscoveryNotSupported: handler returned wrong status code: got %v want %v",
+			status, http.StatusNotImplemented)
+	}
+}
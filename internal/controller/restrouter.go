@@ -38,9 +38,9 @@ func (c RestController) InitRestRoutes() {
 	c.addReservedRoute(common.APIVersionRoute, versionFunc).Methods(http.MethodGet)
 
 	common.LoggingClient.Debug("init command rest controller")
-	c.addReservedRoute(common.APIAllCommandRoute, commandAllFunc).Methods(http.MethodGet, http.MethodPut)
-	c.addReservedRoute(common.APIIdCommandRoute, commandFunc).Methods(http.MethodGet, http.MethodPut)
-	c.addReservedRoute(common.APINameCommandRoute, commandFunc).Methods(http.MethodGet, http.MethodPut)
+	c.addReservedRoute(common.APIAllCommandRoute, timeoutHandler(commandAllFunc)).Methods(http.MethodGet, http.MethodPut)
+	c.addReservedRoute(common.APIIdCommandRoute, timeoutHandler(commandFunc)).Methods(http.MethodGet, http.MethodPut)
+	c.addReservedRoute(common.APINameCommandRoute, timeoutHandler(commandFunc)).Methods(http.MethodGet, http.MethodPut)
 
 	common.LoggingClient.Debug("init callback rest controller")
 	c.addReservedRoute(common.APICallbackRoute, callbackFunc)
@@ -49,6 +49,11 @@ func (c RestController) InitRestRoutes() {
 	c.addReservedRoute(common.APIDiscoveryRoute, discoveryFunc).Methods(http.MethodPost)
 	c.addReservedRoute(common.APITransformRoute, transformFunc).Methods(http.MethodGet)
 
+	common.LoggingClient.Debug("init autoevent rest controller")
+	c.addReservedRoute(common.APIAutoEventRoute, autoEventStatusFunc).Methods(http.MethodGet)
+	c.addReservedRoute(common.APIAutoEventNameRoute, autoEventStatusFunc).Methods(http.MethodGet)
+	c.addReservedRoute(common.APIAutoEventActionRoute, autoEventActionFunc).Methods(http.MethodPost, http.MethodPut, http.MethodDelete)
+
 	common.LoggingClient.Debug("init the metrics and config rest controller each")
 	c.addReservedRoute(common.APIMetricsRoute, metricsHandler).Methods(http.MethodGet)
 	c.addReservedRoute(common.APIConfigRoute, configHandler).Methods(http.MethodGet)
@@ -31,6 +31,7 @@ import (
 func init() {
 	lc := logger.NewClient("update_test", false, "./device-simple.log", "DEBUG")
 	common.LoggingClient = lc
+	common.CurrentConfig = &common.Config{}
 }
 
 func TestAddRoute(t *testing.T) {
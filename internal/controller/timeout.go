@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/controller/correlation"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+)
+
+// timeoutMargin is how long the timeout handler waits, after the command
+// deadline, for the wrapped handler to write its own response to the timeout.
+const timeoutMargin = 100 * time.Millisecond
+
+// ErrorResponse is the structured body of the timeout error responses.
+type ErrorResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message"`
+}
+
+// timeoutHandler wraps the handler as a fallback of the command deadline of
+// the Service.Timeout configuration, which the handler enforces itself by
+// responding with 504. If the handler doesn't complete within timeoutMargin
+// after the deadline, a 503 response is sent with a structured error body, the
+// request context is cancelled, and anything the handler writes afterwards is
+// dropped.
+func timeoutHandler(handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		timeout := time.Duration(common.CurrentConfig.Service.Timeout) * time.Millisecond
+		if timeout <= 0 {
+			handler(w, req)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		req = req.WithContext(ctx)
+
+		done := make(chan struct{})
+		panicCh := make(chan interface{}, 1)
+		tw := &timeoutWriter{w: w, header: make(http.Header)}
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicCh <- p
+				}
+			}()
+			handler(tw, req)
+			close(done)
+		}()
+
+		timer := time.NewTimer(timeout + timeoutMargin)
+		defer timer.Stop()
+		select {
+		case p := <-panicCh:
+			panic(p)
+		case <-done:
+			tw.flush()
+			return
+		case <-timer.C:
+		}
+
+		tw.mutex.Lock()
+		defer tw.mutex.Unlock()
+		tw.timedOut = true
+
+		correlationId := correlation.FromContext(ctx)
+		if ctx.Err() == context.Canceled {
+			common.LoggingClient.Info(fmt.Sprintf("request cancelled by the client: %s %s", req.Method, req.URL.Path), clients.CorrelationHeader, correlationId)
+			return
+		}
+
+		msg := fmt.Sprintf("request timed out after %v: %s %s", timeout, req.Method, req.URL.Path)
+		common.LoggingClient.Error(msg, clients.CorrelationHeader, correlationId)
+		writeErrorResponse(w, msg, http.StatusServiceUnavailable)
+	}
+}
+
+// writeErrorResponse writes a structured error response with the status code.
+func writeErrorResponse(w http.ResponseWriter, msg string, code int) {
+	w.Header().Set(clients.ContentType, clients.ContentTypeJSON)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(ErrorResponse{StatusCode: code, Message: msg})
+}
+
+// timeoutWriter buffers the response of the wrapped handler until it completes,
+// the response is discarded once the request is timed out.
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	timedOut    bool
+	mutex       sync.Mutex
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	return tw.buf.Write(p)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.writeHeader(code)
+}
+
+func (tw *timeoutWriter) writeHeader(code int) {
+	tw.wroteHeader = true
+	tw.code = code
+}
+
+func (tw *timeoutWriter) flush() {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	dst := tw.w.Header()
+	for k, vv := range tw.header {
+		dst[k] = vv
+	}
+	if !tw.wroteHeader {
+		tw.code = http.StatusOK
+	}
+	tw.w.WriteHeader(tw.code)
+	tw.w.Write(tw.buf.Bytes())
+}
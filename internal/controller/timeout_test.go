@@ -0,0 +1,79 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutHandler(t *testing.T) {
+	common.CurrentConfig = &common.Config{Service: common.ServiceInfo{Timeout: 50}}
+	defer func() {
+		common.CurrentConfig = &common.Config{}
+	}()
+
+	lateWrite := make(chan error, 1)
+	tests := []struct {
+		name         string
+		handler      func(http.ResponseWriter, *http.Request)
+		expectedCode int
+		structured   bool
+	}{
+		{"InTime", func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			io.WriteString(w, statusOK)
+		}, http.StatusAccepted, false},
+		// the command deadline of the handler expires before the fallback of
+		// the wrapper, whatever the scheduling of the goroutines
+		{"HandlerRespondsToTimeout", func(w http.ResponseWriter, req *http.Request) {
+			ctx, cancel := context.WithTimeout(req.Context(), 50*time.Millisecond)
+			defer cancel()
+			<-ctx.Done()
+			writeCommandError(w, "driver timed out", http.StatusGatewayTimeout)
+		}, http.StatusGatewayTimeout, true},
+		{"HandlerRespondsLateWithinMargin", func(w http.ResponseWriter, req *http.Request) {
+			time.Sleep(50*time.Millisecond + timeoutMargin/2)
+			writeCommandError(w, "driver timed out", http.StatusGatewayTimeout)
+		}, http.StatusGatewayTimeout, true},
+		{"HandlerIgnoresTimeout", func(w http.ResponseWriter, req *http.Request) {
+			time.Sleep(300 * time.Millisecond)
+			_, err := io.WriteString(w, statusOK)
+			lateWrite <- err
+		}, http.StatusServiceUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/device/name/test/test", nil)
+			rr := httptest.NewRecorder()
+			timeoutHandler(tt.handler)(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.structured {
+				var errResp ErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &errResp); err != nil {
+					t.Fatalf("the response body is not a structured error: %v", err)
+				}
+				assert.Equal(t, tt.expectedCode, errResp.StatusCode)
+				assert.NotEmpty(t, errResp.Message)
+			} else {
+				assert.Equal(t, statusOK, rr.Body.String())
+			}
+		})
+	}
+
+	assert.Equal(t, http.ErrHandlerTimeout, <-lateWrite, "the late write of the handler should be dropped")
+}
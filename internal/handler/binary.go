@@ -0,0 +1,92 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/ugorji/go/codec"
+)
+
+// ContentTypeOctetStream is the media type of a raw binary request body.
+const ContentTypeOctetStream = "application/octet-stream"
+
+// cborHeaderMaxLen is the maximum length of the header of a CBOR byte string.
+const cborHeaderMaxLen = 9
+
+// binaryContentType returns the media type of the Content-Type header of the
+// request body, ok is false unless the body is a raw binary value or a CBOR
+// byte string.
+func binaryContentType(header string) (contentType string, ok bool) {
+	contentType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", false
+	}
+	contentType = strings.ToLower(contentType)
+	return contentType, contentType == ContentTypeOctetStream || contentType == clients.ContentTypeCBOR
+}
+
+// MaxCommandBodyLen returns the maximum length of the body of a command request
+// with the Content-Type. A raw binary or CBOR body is a single Binary value of
+// up to MaxBinaryBytes, a JSON body holds the parameters of up to MaxCmdOps
+// DeviceResources of up to MaxCmdValueLen each, or a base64 encoded Binary value.
+func MaxCommandBodyLen(contentType string) int64 {
+	if _, ok := binaryContentType(contentType); ok {
+		return dsModels.MaxBinaryBytes + cborHeaderMaxLen
+	}
+
+	maxLen := int64(base64.StdEncoding.EncodedLen(dsModels.MaxBinaryBytes)) + int64(common.CurrentConfig.Device.MaxCmdValueLen)
+	if paramsLen := int64(common.CurrentConfig.Device.MaxCmdOps) * int64(common.CurrentConfig.Device.MaxCmdValueLen); paramsLen > maxLen {
+		maxLen = paramsLen
+	}
+	return maxLen
+}
+
+// createCommandValueFromBody creates the CommandValue of a Binary DeviceResource
+// from a raw binary or CBOR request body.
+func createCommandValueFromBody(dr *contract.DeviceResource, contentType string, body string) (*dsModels.CommandValue, error) {
+	if dsModels.ParseValueType(dr.Properties.Value.Type) != dsModels.Binary {
+		return nil, fmt.Errorf("the %s request body can only be written to a Binary DeviceResource, %s is %s",
+			contentType, dr.Name, dr.Properties.Value.Type)
+	}
+
+	value := []byte(body)
+	if contentType == clients.ContentTypeCBOR {
+		value = nil
+		if err := codec.NewDecoderBytes([]byte(body), &codec.CborHandle{}).Decode(&value); err != nil {
+			return nil, fmt.Errorf("the CBOR request body should be a byte string: %v", err)
+		}
+	}
+	return dsModels.NewBinaryValue(dr.Name, time.Now().UnixNano(), value)
+}
+
+// parseBinaryWriteBody creates the CommandValue of the Command from a raw binary
+// or CBOR request body, the Command should write a single DeviceResource.
+func parseBinaryWriteBody(profileName string, ros []contract.ResourceOperation, contentType string, body string) ([]*dsModels.CommandValue, error) {
+	if len(ros) != 1 {
+		return nil, fmt.Errorf("the %s request body can only be written to a single DeviceResource, the Command writes %d", contentType, len(ros))
+	}
+	dr, ok := cache.Profiles().DeviceResource(profileName, ros[0].DeviceResource)
+	if !ok {
+		return nil, fmt.Errorf("the parameter %s does not match any DeviceResource in DeviceProfile", ros[0].DeviceResource)
+	}
+
+	cv, err := createCommandValueFromBody(&dr, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	return []*dsModels.CommandValue{cv}, nil
+}
@@ -0,0 +1,132 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+var binaryResource = contract.DeviceResource{
+	Name:       "Firmware",
+	Properties: contract.ProfileProperty{Value: contract.PropertyValue{Type: "Binary", ReadWrite: "W"}},
+}
+
+func encodeCBOR(t *testing.T, v interface{}) string {
+	var b []byte
+	if err := codec.NewEncoderBytes(&b, &codec.CborHandle{}).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestBinaryContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    string
+		ok          bool
+	}{
+		{"application/octet-stream", ContentTypeOctetStream, true},
+		{"Application/Octet-Stream; name=firmware.bin", ContentTypeOctetStream, true},
+		{"application/cbor", clients.ContentTypeCBOR, true},
+		{"application/json", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			contentType, ok := binaryContentType(tt.contentType)
+			assert.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.expected, contentType)
+			}
+		})
+	}
+}
+
+func TestMaxCommandBodyLen(t *testing.T) {
+	config := common.CurrentConfig
+	common.CurrentConfig = &common.Config{Device: common.DeviceInfo{MaxCmdOps: 128, MaxCmdValueLen: 256}}
+	defer func() {
+		common.CurrentConfig = config
+	}()
+
+	assert.Equal(t, int64(dsModels.MaxBinaryBytes+cborHeaderMaxLen), MaxCommandBodyLen(ContentTypeOctetStream))
+	assert.Equal(t, int64(dsModels.MaxBinaryBytes+cborHeaderMaxLen), MaxCommandBodyLen(clients.ContentTypeCBOR))
+	// the base64 encoding of a Binary value is longer than the other parameters
+	assert.Equal(t, int64(base64.StdEncoding.EncodedLen(dsModels.MaxBinaryBytes)+256), MaxCommandBodyLen(clients.ContentTypeJSON))
+
+	common.CurrentConfig.Device.MaxCmdValueLen = 1 << 20
+	assert.Equal(t, int64(128<<20), MaxCommandBodyLen(""))
+}
+
+func TestCreateCommandValueFromBody(t *testing.T) {
+	int8Resource := contract.DeviceResource{Name: "Int8", Properties: contract.ProfileProperty{Value: contract.PropertyValue{Type: "Int8"}}}
+	tests := []struct {
+		testName    string
+		dr          contract.DeviceResource
+		contentType string
+		body        string
+		expectErr   bool
+	}{
+		{"OctetStream", binaryResource, ContentTypeOctetStream, "\x00\x01\x02", false},
+		{"CBORByteString", binaryResource, clients.ContentTypeCBOR, encodeCBOR(t, []byte{0, 1, 2}), false},
+		{"CBORNotByteString", binaryResource, clients.ContentTypeCBOR, encodeCBOR(t, 12), true},
+		{"NotBinaryResource", int8Resource, ContentTypeOctetStream, "\x00", true},
+		{"ExceedsMaxBinaryBytes", binaryResource, ContentTypeOctetStream, string(make([]byte, dsModels.MaxBinaryBytes+1)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cv, err := createCommandValueFromBody(&tt.dr, tt.contentType, tt.body)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, dsModels.Binary, cv.Type)
+				assert.Equal(t, []byte{0, 1, 2}, cv.BinValue)
+			}
+		})
+	}
+}
+
+func TestExecWriteDeviceResourceBinary(t *testing.T) {
+	tests := []struct {
+		testName    string
+		contentType string
+		body        string
+		expectErr   bool
+	}{
+		{"OctetStream", ContentTypeOctetStream, "\x00\x01\x02", false},
+		{"CBOR", clients.ContentTypeCBOR, encodeCBOR(t, []byte{0, 1, 2}), false},
+		{"Base64JSON", clients.ContentTypeJSON, `{"Firmware":"AAEC"}`, false},
+		{"InvalidBase64JSON", clients.ContentTypeJSON, `{"Firmware":"AAE"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			appErr := execWriteDeviceResource(context.Background(), &deviceIntegerGenerator, &binaryResource, tt.body, tt.contentType)
+			if !tt.expectErr && appErr != nil {
+				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, appErr.Error())
+			} else if tt.expectErr && appErr == nil {
+				t.Errorf("%s expectErr:%v no error thrown", tt.testName, tt.expectErr)
+			}
+		})
+	}
+
+	// a raw binary body cannot be written to a Command with several DeviceResources
+	appErr := execWriteCmd(context.Background(), &deviceIntegerGenerator, "RandomValue_Int8", "\x00", ContentTypeOctetStream)
+	if appErr == nil {
+		t.Error("expected an error writing a raw binary body to a Command with several DeviceResources")
+	}
+}
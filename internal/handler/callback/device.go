@@ -1,7 +1,7 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
 // Copyright (C) 2017-2018 Canonical Ltd
-// Copyright (C) 2018-2019 IOTech Ltd
+// Copyright (C) 2018-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -15,6 +15,7 @@ import (
 	"github.com/edgexfoundry/device-sdk-go/internal/autoevent"
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
 	"github.com/edgexfoundry/device-sdk-go/internal/provision"
 	"github.com/google/uuid"
 )
@@ -60,6 +61,11 @@ func handleDevice(method string, id string) common.AppError {
 			return appErr
 		}
 
+		// the devices which already exist when the service starts don't come
+		// through this callback, Service.Start runs their InitCmd. The failure
+		// of InitCmd doesn't undo the add.
+		handler.StartInitCmd(device)
+
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - starting AutoEvents for device %s", device.Name))
 		autoevent.GetManager().RestartForDevice(device.Name)
 	} else if method == http.MethodPut {
@@ -95,6 +101,7 @@ func handleDevice(method string, id string) common.AppError {
 		if ok {
 			common.LoggingClient.Debug(fmt.Sprintf("Handler - stopping AutoEvents for updated device %s", device.Name))
 			autoevent.GetManager().StopForDevice(device.Name)
+			handler.ExecuteRemoveCmd(device)
 		}
 
 		err := cache.Devices().Remove(id)
@@ -8,6 +8,9 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -24,7 +27,10 @@ import (
 
 // Note, every HTTP request to ServeHTTP is made in a separate goroutine, which
 // means care needs to be taken with respect to shared data accessed through *Server.
-func CommandHandler(vars map[string]string, body string, method string, queryParams string) (*dsModels.Event, common.AppError) {
+// The given context is passed to the Driver with a deadline derived from the
+// Service.Timeout configuration. The contentType is the Content-Type header of
+// the body, a raw binary or CBOR body is written to a Binary DeviceResource.
+func CommandHandler(ctx context.Context, vars map[string]string, body string, contentType string, method string, queryParams string) (*dsModels.Event, common.AppError) {
 	dKey := vars[common.IdVar]
 	cmd := vars[common.CommandVar]
 
@@ -48,7 +54,9 @@ func CommandHandler(vars map[string]string, body string, method string, queryPar
 		return nil, common.NewLockedError(msg, nil)
 	}
 
-	if d.OperatingState == contract.Disabled {
+	// a device disabled by a failed assertion can still be read to recover
+	recovering := strings.ToLower(method) == common.GetCmdMethod && transformer.AssertionRecovering(d.Name)
+	if d.OperatingState == contract.Disabled && !recovering {
 		msg := fmt.Sprintf("%s is disabled; %s", d.Name, method)
 		common.LoggingClient.Error(msg)
 		return nil, common.NewLockedError(msg, nil)
@@ -65,6 +73,9 @@ func CommandHandler(vars map[string]string, body string, method string, queryPar
 		return nil, common.NewServerError(msg, err)
 	}
 
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
 	if !cmdExists {
 		dr, drExists := cache.Profiles().DeviceResource(d.Profile.Name, cmd)
 		if !drExists {
@@ -74,26 +85,32 @@ func CommandHandler(vars map[string]string, body string, method string, queryPar
 		}
 
 		if strings.ToLower(method) == common.GetCmdMethod {
-			return execReadDeviceResource(&d, &dr, queryParams)
+			return execReadDeviceResource(ctx, &d, &dr, queryParams)
 		} else {
-			appErr := execWriteDeviceResource(&d, &dr, body)
+			appErr := execWriteDeviceResource(ctx, &d, &dr, body, contentType)
 			return nil, appErr
 		}
 	}
 
 	if strings.ToLower(method) == common.GetCmdMethod {
-		return execReadCmd(&d, cmd, queryParams)
+		return execReadCmd(ctx, &d, cmd, queryParams)
 	} else {
-		appErr := execWriteCmd(&d, cmd, body)
+		appErr := execWriteCmd(ctx, &d, cmd, body, contentType)
 		return nil, appErr
 	}
 }
 
-func execReadDeviceResource(device *contract.Device, dr *contract.DeviceResource, queryParams string) (*dsModels.Event, common.AppError) {
+func execReadDeviceResource(ctx context.Context, device *contract.Device, dr *contract.DeviceResource, queryParams string) (*dsModels.Event, common.AppError) {
 	var reqs []dsModels.CommandRequest
 	var req dsModels.CommandRequest
 	common.LoggingClient.Debug(fmt.Sprintf("Handler - execReadCmd: deviceResource: %s", dr.Name))
 
+	if err := checkReadable(dr); err != nil {
+		msg := fmt.Sprintf("Handler - execReadCmd: %v", err)
+		common.LoggingClient.Error(msg)
+		return nil, common.NewBadRequestError(msg, err)
+	}
+
 	req.DeviceResourceName = dr.Name
 	req.Attributes = dr.Attributes
 	if queryParams != "" {
@@ -106,18 +123,19 @@ func execReadDeviceResource(device *contract.Device, dr *contract.DeviceResource
 	req.Type = dsModels.ParseValueType(dr.Properties.Value.Type)
 	reqs = append(reqs, req)
 
-	results, err := common.Driver.HandleReadCommands(device.Name, device.Protocols, reqs)
+	results, err := readCommands(ctx, device, reqs)
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execReadCmd: error for Device: %s DeviceResource: %s, %v", device.Name, dr.Name, err)
-		return nil, common.NewServerError(msg, err)
+		return nil, newDriverError(msg, err)
 	}
 
-	return cvsToEvent(device, results, dr.Name)
+	return cvsToEvent(device, results)
 }
 
-func cvsToEvent(device *contract.Device, cvs []*dsModels.CommandValue, cmd string) (*dsModels.Event, common.AppError) {
+func cvsToEvent(device *contract.Device, cvs []*dsModels.CommandValue) (*dsModels.Event, common.AppError) {
 	readings := make([]contract.Reading, 0, common.CurrentConfig.Device.MaxCmdOps)
-	var transformsOK = true
+	qualities := make([]dsModels.Quality, 0, common.CurrentConfig.Device.MaxCmdOps)
+	mediaTypes := make(map[string]string)
 	var err error
 
 	for _, cv := range cvs {
@@ -130,17 +148,21 @@ func cvsToEvent(device *contract.Device, cvs []*dsModels.CommandValue, cmd strin
 		}
 
 		if common.CurrentConfig.Device.DataTransform {
-			err = transformer.TransformReadResult(cv, dr.Properties.Value)
+			err = transformer.TransformReadResource(cv, &dr)
 			if err != nil {
 				common.LoggingClient.Error(fmt.Sprintf("Handler - execReadCmd: CommandValue (%s) transformed failed: %v", cv.String(), err))
-				transformsOK = false
+				cv.DegradeQuality(dsModels.QualityBad, dsModels.ReasonTransformFailed)
 			}
 		}
 
-		err = transformer.CheckAssertion(cv, dr.Properties.Value.Assertion, device)
-		if err != nil {
+		policy, err := transformer.CheckAssertion(cv, &dr, device)
+		if err != nil && policy == transformer.AssertionPolicyDrop {
+			continue
+		} else if err != nil && policy == transformer.AssertionPolicyDisable {
 			common.LoggingClient.Error(fmt.Sprintf("Handler - execReadCmd: Assertion failed for device resource: %s, with value: %v", cv.String(), err))
-			cv = dsModels.NewStringValue(cv.DeviceResourceName, cv.Origin, fmt.Sprintf("Assertion failed for device resource, with value: %s and assertion: %s", cv.String(), dr.Properties.Value.Assertion))
+			cv.DegradeQuality(dsModels.QualityBad, dsModels.ReasonAssertionFailed)
+		} else if err != nil {
+			cv.DegradeQuality(dsModels.QualityUncertain, dsModels.ReasonAssertionFailed)
 		}
 
 		ro, err := cache.Profiles().ResourceOperation(device.Profile.Name, cv.DeviceResourceName, common.GetCmdMethod)
@@ -152,6 +174,7 @@ func cvsToEvent(device *contract.Device, cvs []*dsModels.CommandValue, cmd strin
 				cv = newCV
 			} else {
 				common.LoggingClient.Warn(fmt.Sprintf("Handler - execReadCmd: Resource Operation (%s) mapping value (%s) failed with the mapping table: %v", ro.DeviceCommand, cv.String(), ro.Mappings))
+				cv.DegradeQuality(dsModels.QualityUncertain, dsModels.ReasonMappingFailed)
 				//transformsOK = false  // issue #89 will discuss how to handle there is no mapping matched
 			}
 		}
@@ -163,32 +186,32 @@ func cvsToEvent(device *contract.Device, cvs []*dsModels.CommandValue, cmd strin
 		// been implemened in gxds. TBD at the devices f2f whether this
 		// be killed completely.
 
+		cv, err = transformer.CheckValueLength(cv, common.CurrentConfig.Device.MaxCmdValueLen, common.CurrentConfig.Device.MaxCmdValueLenPolicy)
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execReadCmd: %v", err)
+			common.LoggingClient.Error(msg)
+			return nil, common.NewServerError(msg, err)
+		}
+
 		reading := common.CommandValueToReading(cv, device.Name, dr.Properties.Value.FloatEncoding)
 		readings = append(readings, *reading)
+		qualities = append(qualities, cv.Quality)
+		if mediaType := common.MediaType(cv, dr); mediaType != "" {
+			mediaTypes[reading.Name] = mediaType
+		}
 
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - execReadCmd: device: %s DeviceResource: %v reading: %v", device.Name, cv.DeviceResourceName, reading))
 	}
 
-	if !transformsOK {
-		msg := fmt.Sprintf("Transform failed for dev: %s cmd: %s method: GET", device.Name, cmd)
-		common.LoggingClient.Error(msg)
-		common.LoggingClient.Debug(fmt.Sprintf("Readings: %v", readings))
-		return nil, common.NewServerError(msg, nil)
-	}
-
 	// push to Core Data
 	cevent := contract.Event{Device: device.Name, Readings: readings}
-	event := &dsModels.Event{Event: cevent}
+	event := &dsModels.Event{Event: cevent, MediaTypes: mediaTypes, Qualities: qualities}
 	event.Origin = common.GetUniqueOrigin()
 
-	// TODO: enforce config.MaxCmdValueLen; need to include overhead for
-	// the rest of the reading JSON + Event JSON length?  Should there be
-	// a separate JSON body max limit for retvals & command parameters?
-
 	return event, nil
 }
 
-func execReadCmd(device *contract.Device, cmd string, queryParams string) (*dsModels.Event, common.AppError) {
+func execReadCmd(ctx context.Context, device *contract.Device, cmd string, queryParams string) (*dsModels.Event, common.AppError) {
 	// make ResourceOperations
 	ros, err := cache.Profiles().ResourceOperations(device.Profile.Name, cmd, common.GetCmdMethod)
 	if err != nil {
@@ -220,6 +243,11 @@ func execReadCmd(device *contract.Device, cmd string, queryParams string) (*dsMo
 			common.LoggingClient.Error(msg)
 			return nil, common.NewServerError(msg, nil)
 		}
+		if err = checkReadable(&dr); err != nil {
+			msg := fmt.Sprintf("Handler - execReadCmd: %v", err)
+			common.LoggingClient.Error(msg)
+			return nil, common.NewBadRequestError(msg, err)
+		}
 
 		reqs[i].DeviceResourceName = dr.Name
 		reqs[i].Attributes = dr.Attributes
@@ -233,35 +261,59 @@ func execReadCmd(device *contract.Device, cmd string, queryParams string) (*dsMo
 		reqs[i].Type = dsModels.ParseValueType(dr.Properties.Value.Type)
 	}
 
-	results, err := common.Driver.HandleReadCommands(device.Name, device.Protocols, reqs)
+	results, err := readCommands(ctx, device, reqs)
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execReadCmd: error for Device: %s cmd: %s, %v", device.Name, cmd, err)
-		return nil, common.NewServerError(msg, err)
+		return nil, newDriverError(msg, err)
 	}
 
-	return cvsToEvent(device, results, cmd)
+	return cvsToEvent(device, results)
 }
 
-func execWriteDeviceResource(device *contract.Device, dr *contract.DeviceResource, params string) common.AppError {
-	paramMap, err := parseParams(params)
-	if err != nil {
-		msg := fmt.Sprintf("Handler - execWriteDeviceResource: Put parameters parsing failed: %s", params)
+func execWriteDeviceResource(ctx context.Context, device *contract.Device, dr *contract.DeviceResource, params string, contentType string) common.AppError {
+	if err := checkWritable(dr); err != nil {
+		msg := fmt.Sprintf("Handler - execWriteDeviceResource: %v", err)
 		common.LoggingClient.Error(msg)
 		return common.NewBadRequestError(msg, err)
 	}
 
-	v, ok := paramMap[dr.Name]
-	if !ok && dr.Properties.Value.DefaultValue != "" {
-		v = dr.Properties.Value.DefaultValue
-	} else if !ok {
-		msg := fmt.Sprintf("there is no %s in parameters and no default value in DeviceResource", dr.Name)
-		common.LoggingClient.Error(msg)
-		return common.NewBadRequestError(msg, fmt.Errorf(msg))
+	var cv *dsModels.CommandValue
+	var err error
+	if mediaType, ok := binaryContentType(contentType); ok {
+		cv, err = createCommandValueFromBody(dr, mediaType, params)
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execWriteDeviceResource: %v", err)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, err)
+		}
+	} else {
+		var paramMap map[string]string
+		paramMap, err = parseParams(params)
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execWriteDeviceResource: Put parameters parsing failed: %s", params)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, err)
+		}
+
+		v, ok := paramMap[dr.Name]
+		if !ok && dr.Properties.Value.DefaultValue != "" {
+			v = dr.Properties.Value.DefaultValue
+		} else if !ok {
+			msg := fmt.Sprintf("there is no %s in parameters and no default value in DeviceResource", dr.Name)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, fmt.Errorf(msg))
+		}
+
+		cv, err = createCommandValueFromDR(dr, v)
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execWriteDeviceResource: Put parameters parsing failed: %s", params)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, err)
+		}
 	}
 
-	cv, err := createCommandValueFromDR(dr, v)
-	if err != nil {
-		msg := fmt.Sprintf("Handler - execWriteDeviceResource: Put parameters parsing failed: %s", params)
+	if err = checkValueRange(cv, dr); err != nil {
+		msg := fmt.Sprintf("Handler - execWriteDeviceResource: %v", err)
 		common.LoggingClient.Error(msg)
 		return common.NewBadRequestError(msg, err)
 	}
@@ -273,24 +325,27 @@ func execWriteDeviceResource(device *contract.Device, dr *contract.DeviceResourc
 	reqs[0].Type = cv.Type
 
 	if common.CurrentConfig.Device.DataTransform {
-		err = transformer.TransformWriteParameter(cv, dr.Properties.Value)
+		err = transformer.TransformWriteResource(cv, dr)
 		if err != nil {
 			msg := fmt.Sprintf("Handler - execWriteDeviceResource: CommandValue (%s) transformed failed: %v", cv.String(), err)
 			common.LoggingClient.Error(msg)
 			return common.NewServerError(msg, err)
 		}
+		if appErr := mergeMaskedValues(ctx, device, []contract.DeviceResource{*dr}, reqs, []*dsModels.CommandValue{cv}); appErr != nil {
+			return appErr
+		}
 	}
 
-	err = common.Driver.HandleWriteCommands(device.Name, device.Protocols, reqs, []*dsModels.CommandValue{cv})
+	err = writeCommands(ctx, device, reqs, []*dsModels.CommandValue{cv})
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execWriteDeviceResource: error for Device: %s Device Resource: %s, %v", device.Name, dr.Name, err)
-		return common.NewServerError(msg, err)
+		return newDriverError(msg, err)
 	}
 
 	return nil
 }
 
-func execWriteCmd(device *contract.Device, cmd string, params string) common.AppError {
+func execWriteCmd(ctx context.Context, device *contract.Device, cmd string, params string, contentType string) common.AppError {
 	ros, err := cache.Profiles().ResourceOperations(device.Profile.Name, cmd, common.SetCmdMethod)
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execWriteCmd: can't find ResrouceOperations in Profile(%s) and Command(%s), %v", device.Profile.Name, cmd, err)
@@ -305,7 +360,12 @@ func execWriteCmd(device *contract.Device, cmd string, params string) common.App
 		return common.NewServerError(msg, nil)
 	}
 
-	cvs, err := parseWriteParams(device.Profile.Name, ros, params)
+	var cvs []*dsModels.CommandValue
+	if mediaType, ok := binaryContentType(contentType); ok {
+		cvs, err = parseBinaryWriteBody(device.Profile.Name, ros, mediaType, params)
+	} else {
+		cvs, err = parseWriteParams(device.Profile.Name, ros, params)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execWriteCmd: Put parameters parsing failed: %s", params)
 		common.LoggingClient.Error(msg)
@@ -313,6 +373,7 @@ func execWriteCmd(device *contract.Device, cmd string, params string) common.App
 	}
 
 	reqs := make([]dsModels.CommandRequest, len(cvs))
+	drs := make([]contract.DeviceResource, len(cvs))
 	for i, cv := range cvs {
 		drName := cv.DeviceResourceName
 		common.LoggingClient.Debug(fmt.Sprintf("Handler - execWriteCmd: putting deviceResource: %s", drName))
@@ -328,13 +389,22 @@ func execWriteCmd(device *contract.Device, cmd string, params string) common.App
 			common.LoggingClient.Error(msg)
 			return common.NewServerError(msg, nil)
 		}
+		if err = checkWritable(&dr); err == nil {
+			err = checkValueRange(cv, &dr)
+		}
+		if err != nil {
+			msg := fmt.Sprintf("Handler - execWriteCmd: %v", err)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, err)
+		}
 
+		drs[i] = dr
 		reqs[i].DeviceResourceName = cv.DeviceResourceName
 		reqs[i].Attributes = dr.Attributes
 		reqs[i].Type = cv.Type
 
 		if common.CurrentConfig.Device.DataTransform {
-			err = transformer.TransformWriteParameter(cv, dr.Properties.Value)
+			err = transformer.TransformWriteResource(cv, &dr)
 			if err != nil {
 				msg := fmt.Sprintf("Handler - execWriteCmd: CommandValue (%s) transformed failed: %v", cv.String(), err)
 				common.LoggingClient.Error(msg)
@@ -343,10 +413,16 @@ func execWriteCmd(device *contract.Device, cmd string, params string) common.App
 		}
 	}
 
-	err = common.Driver.HandleWriteCommands(device.Name, device.Protocols, reqs, cvs)
+	if common.CurrentConfig.Device.DataTransform {
+		if appErr := mergeMaskedValues(ctx, device, drs, reqs, cvs); appErr != nil {
+			return appErr
+		}
+	}
+
+	err = writeCommands(ctx, device, reqs, cvs)
 	if err != nil {
 		msg := fmt.Sprintf("Handler - execWriteCmd: error for Device: %s cmd: %s, %v", device.Name, cmd, err)
-		return common.NewServerError(msg, err)
+		return newDriverError(msg, err)
 	}
 
 	return nil
@@ -403,20 +479,77 @@ func parseWriteParams(profileName string, ros []contract.ResourceOperation, para
 	return result, nil
 }
 
+// parseParams parses the Write parameters. A JSON string is unquoted, the other
+// JSON values (numbers, booleans, arrays and objects) are kept in their JSON
+// format, so they are converted to the type of the DeviceResource the same way
+// as the values in string format.
 func parseParams(params string) (paramMap map[string]string, err error) {
-	err = json.Unmarshal([]byte(params), &paramMap)
+	var rawMap map[string]json.RawMessage
+	err = json.Unmarshal([]byte(params), &rawMap)
 	if err != nil {
 		common.LoggingClient.Error(fmt.Sprintf("parsing Write parameters failed %s, %v", params, err))
 		return
 	}
 
-	if len(paramMap) == 0 {
+	if len(rawMap) == 0 {
 		err = fmt.Errorf("no parameters specified")
 		return
 	}
+
+	paramMap = make(map[string]string, len(rawMap))
+	for k, raw := range rawMap {
+		raw = bytes.TrimSpace(raw)
+		if string(raw) == "null" {
+			err = fmt.Errorf("the value of parameter %s is null", k)
+			common.LoggingClient.Error(fmt.Sprintf("parsing Write parameters failed %s, %v", params, err))
+			return nil, err
+		} else if len(raw) == 0 || raw[0] != '"' {
+			paramMap[k] = string(raw)
+			continue
+		}
+		var v string
+		if err = json.Unmarshal(raw, &v); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("parsing Write parameters failed %s, %v", params, err))
+			return nil, err
+		}
+		paramMap[k] = v
+	}
 	return
 }
 
+// parseObjectParam parses the JSON object v, the numbers are decoded to
+// json.Number to keep them as they are in the request.
+func parseObjectParam(v string) (map[string]interface{}, error) {
+	var o map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(v))
+	decoder.UseNumber()
+	if err := decoder.Decode(&o); err != nil {
+		return nil, fmt.Errorf("%s is not a JSON object: %v", v, err)
+	} else if o == nil || decoder.More() {
+		return nil, fmt.Errorf("%s is not a JSON object", v)
+	}
+	return o, nil
+}
+
+// parseArrayParam parses the JSON array v and calls parseElement with every
+// element in string format, the elements may be JSON numbers, booleans or strings.
+func parseArrayParam(v string, elementType dsModels.ValueType, parseElement func(string) error) error {
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(v), &elements); err != nil {
+		return fmt.Errorf("%s is not a JSON array: %v", v, err)
+	}
+	for i, raw := range elements {
+		var e string
+		if err := json.Unmarshal(raw, &e); err != nil {
+			e = string(raw)
+		}
+		if err := parseElement(e); err != nil {
+			return fmt.Errorf("element %d of the array: %v", i, outOfRangeError(elementType, e, err))
+		}
+	}
+	return nil
+}
+
 func createCommandValueFromRO(profileName string, ro *contract.ResourceOperation, v string) (*dsModels.CommandValue, error) {
 	dr, ok := cache.Profiles().DeviceResource(profileName, ro.DeviceResource)
 	if !ok {
@@ -486,9 +619,120 @@ func createCommandValueFromDR(dr *contract.DeviceResource, v string) (*dsModels.
 	case "float64":
 		value, err = strconv.ParseFloat(v, 64)
 		t = dsModels.Float64
+	case "binary":
+		b, e := base64.StdEncoding.DecodeString(v)
+		if e != nil {
+			err = fmt.Errorf("the value of a Binary DeviceResource should be encoded in base64: %v", e)
+			break
+		}
+		// NewBinaryValue enforces the MaxBinaryBytes limit
+		return dsModels.NewBinaryValue(dr.Name, origin, b)
+	case "object":
+		value, err = parseObjectParam(v)
+		t = dsModels.Object
+	case "boolarray":
+		a := []bool{}
+		err = parseArrayParam(v, dsModels.Bool, func(s string) error {
+			n, e := strconv.ParseBool(s)
+			a = append(a, n)
+			return e
+		})
+		value = a
+		t = dsModels.BoolArray
+	case "uint8array":
+		a := []uint8{}
+		err = parseArrayParam(v, dsModels.Uint8, func(s string) error {
+			n, e := strconv.ParseUint(s, 10, 8)
+			a = append(a, uint8(n))
+			return e
+		})
+		value = a
+		t = dsModels.Uint8Array
+	case "uint16array":
+		a := []uint16{}
+		err = parseArrayParam(v, dsModels.Uint16, func(s string) error {
+			n, e := strconv.ParseUint(s, 10, 16)
+			a = append(a, uint16(n))
+			return e
+		})
+		value = a
+		t = dsModels.Uint16Array
+	case "uint32array":
+		a := []uint32{}
+		err = parseArrayParam(v, dsModels.Uint32, func(s string) error {
+			n, e := strconv.ParseUint(s, 10, 32)
+			a = append(a, uint32(n))
+			return e
+		})
+		value = a
+		t = dsModels.Uint32Array
+	case "uint64array":
+		a := []uint64{}
+		err = parseArrayParam(v, dsModels.Uint64, func(s string) error {
+			n, e := strconv.ParseUint(s, 10, 64)
+			a = append(a, n)
+			return e
+		})
+		value = a
+		t = dsModels.Uint64Array
+	case "int8array":
+		a := []int8{}
+		err = parseArrayParam(v, dsModels.Int8, func(s string) error {
+			n, e := strconv.ParseInt(s, 10, 8)
+			a = append(a, int8(n))
+			return e
+		})
+		value = a
+		t = dsModels.Int8Array
+	case "int16array":
+		a := []int16{}
+		err = parseArrayParam(v, dsModels.Int16, func(s string) error {
+			n, e := strconv.ParseInt(s, 10, 16)
+			a = append(a, int16(n))
+			return e
+		})
+		value = a
+		t = dsModels.Int16Array
+	case "int32array":
+		a := []int32{}
+		err = parseArrayParam(v, dsModels.Int32, func(s string) error {
+			n, e := strconv.ParseInt(s, 10, 32)
+			a = append(a, int32(n))
+			return e
+		})
+		value = a
+		t = dsModels.Int32Array
+	case "int64array":
+		a := []int64{}
+		err = parseArrayParam(v, dsModels.Int64, func(s string) error {
+			n, e := strconv.ParseInt(s, 10, 64)
+			a = append(a, n)
+			return e
+		})
+		value = a
+		t = dsModels.Int64Array
+	case "float32array":
+		a := []float32{}
+		err = parseArrayParam(v, dsModels.Float32, func(s string) error {
+			n, e := strconv.ParseFloat(s, 32)
+			a = append(a, float32(n))
+			return e
+		})
+		value = a
+		t = dsModels.Float32Array
+	case "float64array":
+		a := []float64{}
+		err = parseArrayParam(v, dsModels.Float64, func(s string) error {
+			n, e := strconv.ParseFloat(s, 64)
+			a = append(a, n)
+			return e
+		})
+		value = a
+		t = dsModels.Float64Array
 	}
 
 	if err != nil {
+		err = outOfRangeError(t, v, err)
 		common.LoggingClient.Error(fmt.Sprintf("Handler - Command: Parsing parameter value (%s) to %s failed: %v", v, dr.Properties.Value.Type, err))
 		return result, err
 	}
@@ -498,11 +742,14 @@ func createCommandValueFromDR(dr *contract.DeviceResource, v string) (*dsModels.
 	return result, err
 }
 
-func CommandAllHandler(cmd string, body string, method string, queryParams string) ([]*dsModels.Event, common.AppError) {
+func CommandAllHandler(ctx context.Context, cmd string, body string, contentType string, method string, queryParams string) ([]*dsModels.Event, common.AppError) {
 	common.LoggingClient.Debug(fmt.Sprintf("Handler - CommandAll: execute the %s command %s from all operational devices", method, cmd))
 	devices := filterOperationalDevices(cache.Devices().All())
 
 	devCount := len(devices)
+	ctx, cancel := withCommandTimeout(ctx)
+	defer cancel()
+
 	var waitGroup sync.WaitGroup
 	waitGroup.Add(devCount)
 	cmdResults := make(chan struct {
@@ -516,9 +763,9 @@ func CommandAllHandler(cmd string, body string, method string, queryParams strin
 			var event *dsModels.Event = nil
 			var appErr common.AppError = nil
 			if strings.ToLower(method) == common.GetCmdMethod {
-				event, appErr = execReadCmd(device, cmd, queryParams)
+				event, appErr = execReadCmd(ctx, device, cmd, queryParams)
 			} else {
-				appErr = execWriteCmd(device, cmd, body)
+				appErr = execWriteCmd(ctx, device, cmd, body, contentType)
 			}
 			cmdResults <- struct {
 				event  *dsModels.Event
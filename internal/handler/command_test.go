@@ -16,6 +16,7 @@ import (
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	"github.com/edgexfoundry/device-sdk-go/internal/mock"
 	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
 	"github.com/google/uuid"
@@ -228,6 +229,73 @@ func TestCreateCommandValueForParam(t *testing.T) {
 	}
 }
 
+func TestCreateCommandValueFromDRStructured(t *testing.T) {
+	tests := []struct {
+		testName  string
+		valueType string
+		v         string
+		expected  string
+		expectErr bool
+	}{
+		{"BoolArrayPass", "BoolArray", `[true, false]`, "[true,false]", false},
+		{"Uint8ArrayPass", "Uint8Array", `[0, 255]`, "[0,255]", false},
+		{"Uint8ArrayOverflowFail", "Uint8Array", `[0, 256]`, "", true},
+		{"Int16ArrayPass", "Int16Array", `[-1, 2]`, "[-1,2]", false},
+		{"Int32ArrayStringElementsPass", "Int32Array", `["-1", "2"]`, "[-1,2]", false},
+		{"Int64ArrayEmptyPass", "Int64Array", `[]`, "[]", false},
+		{"Float32ArrayPass", "Float32Array", `[1.5, -2]`, "[1.5,-2]", false},
+		{"Float64ArrayWordFail", "Float64Array", `[1.5, "hello"]`, "", true},
+		{"Float64ArrayNotArrayFail", "Float64Array", `1.5`, "", true},
+		{"ObjectPass", "Object", `{"a": {"b": [1, 2]}, "c": 12345678901234567890}`, `{"a":{"b":[1,2]},"c":12345678901234567890}`, false},
+		{"ObjectNullFail", "Object", `null`, "", true},
+		{"ObjectTrailingDataFail", "Object", `{"a": 1} {}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dr := &contract.DeviceResource{Name: "array", Properties: contract.ProfileProperty{Value: contract.PropertyValue{Type: tt.valueType}}}
+			cv, err := createCommandValueFromDR(dr, tt.v)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("%s expectErr:%v no error thrown", tt.testName, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, err)
+			}
+			if cv.Type != dsModels.ParseValueType(tt.valueType) || cv.ValueToString() != tt.expected {
+				t.Errorf("%s incorrect parsing. valueType: %v result: %s", tt.testName, cv.Type, cv.ValueToString())
+			}
+		})
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		testName  string
+		params    string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{"String", `{"a":"1"}`, map[string]string{"a": "1"}, false},
+		{"Array", `{"a":"1","b":[1, 2]}`, map[string]string{"a": "1", "b": "[1, 2]"}, false},
+		{"Object", `{"a":{"b":[1, 2]}}`, map[string]string{"a": `{"b":[1, 2]}`}, false},
+		{"Number", `{"a":-1.5e3,"b":18446744073709551615}`, map[string]string{"a": "-1.5e3", "b": "18446744073709551615"}, false},
+		{"Bool", `{"a":true}`, map[string]string{"a": "true"}, false},
+		{"Null", `{"a":null}`, nil, true},
+		{"Empty", `{}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			paramMap, err := parseParams(tt.params)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, err)
+			}
+			assert.Equal(t, tt.expected, paramMap)
+		})
+	}
+}
+
 func TestParseWriteParams(t *testing.T) {
 	profileName := mock.ProfileInt
 
@@ -270,6 +338,76 @@ func TestParseWriteParams(t *testing.T) {
 	}
 }
 
+func TestParseWriteParamsTypedValues(t *testing.T) {
+	profileName := mock.ProfileInt
+	ros, _ := cache.Profiles().ResourceOperations(profileName, "RandomValue_Int8", common.SetCmdMethod)
+
+	tests := []struct {
+		testName    string
+		params      string
+		expected    string
+		expectedErr string
+	}{
+		{"Number", `{"RandomValue_Int8":-12}`, "-12", ""},
+		{"String", `{"RandomValue_Int8":"-12"}`, "-12", ""},
+		{"Overflow", `{"RandomValue_Int8":128}`, "", "value 128 overflows Int8, the value should be between -128 and 127"},
+		{"NotInteger", `{"RandomValue_Int8":1.5}`, "", `strconv.ParseInt: parsing "1.5": invalid syntax`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cvs, err := parseWriteParams(profileName, ros, tt.params)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error %s, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected parse error params:%s %s", tt.params, err.Error())
+			}
+			assert.Equal(t, tt.expected, cvs[0].ValueToString())
+		})
+	}
+}
+
+func TestCreateCommandValueFromDRTyped(t *testing.T) {
+	tests := []struct {
+		testName    string
+		valueType   string
+		v           string
+		expected    string
+		expectedErr string
+	}{
+		{"BoolPass", "Bool", "true", "true", ""},
+		{"Uint64MaxPass", "Uint64", "18446744073709551615", "18446744073709551615", ""},
+		{"Uint64Overflow", "Uint64", "18446744073709551616", "", "value 18446744073709551616 overflows Uint64, the value should be between 0 and 18446744073709551615"},
+		{"Float32Overflow", "Float32", "1e39", "", "value 1e39 overflows Float32, the value should be between -3.4028234663852886e+38 and 3.4028234663852886e+38"},
+		{"Uint16ArrayOverflow", "Uint16Array", "[1, 65536]", "", "element 1 of the array: value 65536 overflows Uint16, the value should be between 0 and 65535"},
+		{"BinaryPass", "Binary", "AAEC", "", ""},
+		{"BinaryNotBase64", "Binary", "AAE", "", "the value of a Binary DeviceResource should be encoded in base64: illegal base64 data at input byte 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dr := &contract.DeviceResource{Name: "resource", Properties: contract.ProfileProperty{Value: contract.PropertyValue{Type: tt.valueType}}}
+			cv, err := createCommandValueFromDR(dr, tt.v)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("expected error %s, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if cv.Type == dsModels.Binary {
+				assert.Equal(t, []byte{0, 1, 2}, cv.BinValue)
+			} else {
+				assert.Equal(t, tt.expected, cv.ValueToString())
+			}
+		})
+	}
+}
+
 func TestExecReadCmd(t *testing.T) {
 	tests := []struct {
 		testName    string
@@ -280,7 +418,8 @@ func TestExecReadCmd(t *testing.T) {
 	}{
 		{"CmdExecutionPass", &deviceIntegerGenerator, "RandomValue_Int8", "", false},
 		{"CmdNotFound", &deviceIntegerGenerator, "InexistentCmd", "", true},
-		{"ValueTransformFail", &deviceIntegerGenerator, "ResourceTestTransform_Fail", "", true},
+		//The expectErr on the test below is false because execReadCmd does NOT throw an error when the transform failed
+		{"ValueTransformFail", &deviceIntegerGenerator, "ResourceTestTransform_Fail", "", false},
 		{"ValueAssertionPass", &deviceIntegerGenerator, "ResourceTestAssertion_Pass", "", false},
 		//The expectErr on the test below is false because execReadCmd does NOT throw an error when assertion failed
 		{"ValueAssertionFail", &deviceIntegerGenerator, "ResourceTestAssertion_Fail", "", false},
@@ -300,7 +439,7 @@ func TestExecReadCmd(t *testing.T) {
 					common.CurrentConfig.Device.MaxCmdOps = 128
 				}()
 			}
-			v, err := execReadCmd(tt.device, tt.cmd, tt.queryParams)
+			v, err := execReadCmd(context.Background(), tt.device, tt.cmd, tt.queryParams)
 			if !tt.expectErr && err != nil {
 				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, err)
 				return
@@ -309,12 +448,15 @@ func TestExecReadCmd(t *testing.T) {
 				t.Errorf("%s expectErr:%v no error thrown", tt.testName, tt.expectErr)
 				return
 			}
-			//The way to determine whether the assertion passed or failed is to see the return value contains "Assertion failed" or not
-			if tt.testName == "ValueAssertionPass" && strings.Contains(v.Readings[0].Value, "Assertion failed") {
+			//The way to determine whether the transform or the assertion failed is to see the quality of the reading
+			if tt.testName == "ValueTransformFail" && v.Qualities[0] != (dsModels.Quality{Status: dsModels.QualityBad, Reason: dsModels.ReasonTransformFailed}) {
+				t.Errorf("%s expect data transform failed, quality: %v", tt.testName, v.Qualities[0])
+			}
+			if tt.testName == "ValueAssertionPass" && v.HasDegradedReadings() {
 				t.Errorf("%s expect data assertion pass", tt.testName)
 			}
-			if tt.testName == "ValueAssertionFail" && !strings.Contains(v.Readings[0].Value, "Assertion failed") {
-				t.Errorf("%s expect data assertion failed", tt.testName)
+			if tt.testName == "ValueAssertionFail" && v.Qualities[0] != (dsModels.Quality{Status: dsModels.QualityBad, Reason: dsModels.ReasonAssertionFailed}) {
+				t.Errorf("%s expect data assertion failed, quality: %v", tt.testName, v.Qualities[0])
 			}
 			// issue #89 will discuss how to handle there is no mapping matched
 			if tt.testName == "ValueMappingPass" && v.Readings[0].Value == strconv.Itoa(int(mock.Int8Value)) {
@@ -323,13 +465,16 @@ func TestExecReadCmd(t *testing.T) {
 			if tt.testName == "ValueMappingFail" && v.Readings[0].Value != strconv.Itoa(int(mock.Int8Value)) {
 				t.Errorf("%s expect data mapping failed", tt.testName)
 			}
+			if tt.testName == "ValueMappingFail" && v.Qualities[0].Status != dsModels.QualityUncertain {
+				t.Errorf("%s expect an uncertain quality, quality: %v", tt.testName, v.Qualities[0])
+			}
 		})
 	}
 }
 
 func TestExecWriteCmd(t *testing.T) {
 	var (
-		paramsInt8                      = `{"RandomValue_Int8":"123"}`
+		paramsInt8                      = `{"ResourceTestWrite_Int8":"123"}`
 		paramsError                     = `{"Error":"error"}`
 		paramsTransformFail             = `{"ResourceTestTransform_Fail":"123"}`
 		paramsNoDeviceResourceForResult = `{"error":""}`
@@ -341,7 +486,7 @@ func TestExecWriteCmd(t *testing.T) {
 		params    string
 		expectErr bool
 	}{
-		{"CmdExecutionPass", &deviceIntegerGenerator, "RandomValue_Int8", paramsInt8, false},
+		{"CmdExecutionPass", &deviceIntegerGenerator, "ResourceTestWrite_Int8", paramsInt8, false},
 		{"CmdNotFound", &deviceIntegerGenerator, "inexistentCmd", paramsInt8, true},
 		{"MaxCmdOpsExceeded", &deviceIntegerGenerator, "Error", paramsInt8, true},
 		{"NoDeviceResourceForOperation", &deviceIntegerGenerator, "NoDeviceResourceForOperation", paramsError, true},
@@ -357,7 +502,7 @@ func TestExecWriteCmd(t *testing.T) {
 					common.CurrentConfig.Device.MaxCmdOps = 128
 				}()
 			}
-			appErr := execWriteCmd(tt.device, tt.cmd, tt.params)
+			appErr := execWriteCmd(context.Background(), tt.device, tt.cmd, tt.params, clients.ContentTypeJSON)
 			if !tt.expectErr && appErr != nil {
 				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, appErr.Error())
 				return
@@ -382,12 +527,12 @@ func TestCommandAllHandler(t *testing.T) {
 		{"PartOfReadCommandExecutionSuccess", "RandomValue_Uint8", "", "", methodGet, false},
 		{"PartOfReadCommandExecutionSuccessWithQueryParams", "RandomValue_Uint8", "", "test=test&test2=test2", methodGet, false},
 		{"PartOfReadCommandExecutionFail", "error", "", "", methodGet, true},
-		{"PartOfWriteCommandExecutionSuccess", "RandomValue_Uint8", `{"RandomValue_Uint8":"123"}`, "", methodSet, false},
-		{"PartOfWriteCommandExecutionFail", "error", `{"RandomValue_Uint8":"123"}`, "", methodSet, true},
+		{"PartOfWriteCommandExecutionSuccess", "ResourceTestWrite_Uint8", `{"ResourceTestWrite_Uint8":"123"}`, "", methodSet, false},
+		{"PartOfWriteCommandExecutionFail", "error", `{"ResourceTestWrite_Uint8":"123"}`, "", methodSet, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
-			_, appErr := CommandAllHandler(tt.cmd, tt.body, tt.method, tt.queryParams)
+			_, appErr := CommandAllHandler(context.Background(), tt.cmd, tt.body, clients.ContentTypeJSON, tt.method, tt.queryParams)
 			if !tt.expectErr && appErr != nil {
 				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, appErr.Error())
 				return
@@ -410,7 +555,7 @@ func TestCommandHandler(t *testing.T) {
 		varsOperatingStateDisabled  = map[string]string{"name": mock.OperatingStateDisabled.Name, "command": "testrandfloat32"}
 		varsProfileNotFound         = map[string]string{"name": "Random-Boolean-Generator01", "command": "error"}
 		varsCmdNotFound             = map[string]string{"name": "Random-Integer-Generator01", "command": "error"}
-		varsWriteUint8              = map[string]string{"name": "Random-UnsignedInteger-Generator01", "command": "RandomValue_Uint8"}
+		varsWriteUint8              = map[string]string{"name": "Random-UnsignedInteger-Generator01", "command": "ResourceTestWrite_Uint8"}
 	)
 	if err := cache.Devices().UpdateAdminState(mock.ValidDeviceRandomFloatGenerator.Id, contract.Locked); err != nil {
 		t.Errorf("Fail to update adminState, error: %v", err)
@@ -436,11 +581,13 @@ func TestCommandHandler(t *testing.T) {
 		{"OperatingStateDisabled", varsOperatingStateDisabled, "", methodGet, "", true},
 		{"ProfileNotFound", varsProfileNotFound, "", methodGet, "", true},
 		{"CmdNotFound", varsCmdNotFound, "", methodGet, "", true},
-		{"WriteCommand", varsWriteUint8, `{"RandomValue_Uint8":"123"}`, methodSet, "", false},
+		{"WriteCommand", varsWriteUint8, `{"ResourceTestWrite_Uint8":"123"}`, methodSet, "", false},
+		{"WriteCommandWithJSONNumber", varsWriteUint8, `{"ResourceTestWrite_Uint8":123}`, methodSet, "", false},
+		{"WriteCommandOverflow", varsWriteUint8, `{"ResourceTestWrite_Uint8":256}`, methodSet, "", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
-			_, appErr := CommandHandler(tt.vars, tt.body, tt.method, tt.queryParams)
+			_, appErr := CommandHandler(context.Background(), tt.vars, tt.body, clients.ContentTypeJSON, tt.method, tt.queryParams)
 			if !tt.expectErr && appErr != nil {
 				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, appErr.Error())
 				return
@@ -1,6 +1,7 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
 // Copyright (C) 2017-2018 Canonical Ltd
+// Copyright (C) 2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -9,14 +10,16 @@ package handler
 import (
 	"fmt"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/autodiscovery"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 )
 
-func DiscoveryHandler(requestMap map[string]string) {
+func DiscoveryHandler(requestMap map[string]string) common.AppError {
 	common.LoggingClient.Info(fmt.Sprintf("service: discovery request"))
-}
-
-func TransformHandler(requestMap map[string]string) (map[string]string, common.AppError) {
-	common.LoggingClient.Info(fmt.Sprintf("service: transform request: transformData: %s", requestMap["transformData"]))
-	return requestMap, nil
+	if !autodiscovery.StartDiscovery(common.Discovery) {
+		msg := "another device discovery process is currently running"
+		common.LoggingClient.Info(msg)
+		return common.NewConflictError(msg, nil)
+	}
+	return nil
 }
@@ -0,0 +1,108 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// withCommandTimeout returns a copy of the given context which expires after
+// the Service.Timeout configuration. The context is returned as it is if no
+// timeout is configured.
+func withCommandTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := common.CurrentConfig.Service.Timeout
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+}
+
+// readCommands calls the ContextProtocolDriver if the Driver implements it,
+// otherwise it falls back to the ProtocolDriver. It returns the error of the
+// context if the context is done before the Driver returns, in which case the
+// late result of the Driver is dropped.
+func readCommands(ctx context.Context, device *contract.Device, reqs []dsModels.CommandRequest) ([]*dsModels.CommandValue, error) {
+	type readResult struct {
+		cvs []*dsModels.CommandValue
+		err error
+	}
+
+	resultCh := make(chan readResult, 1)
+	go func() {
+		defer recoverDriverPanic(device.Name, func(err error) { resultCh <- readResult{err: err} })
+
+		var r readResult
+		if driver, ok := common.Driver.(dsModels.ContextProtocolDriver); ok {
+			r.cvs, r.err = driver.HandleReadCommandsWithContext(ctx, device.Name, device.Protocols, reqs)
+		} else {
+			r.cvs, r.err = common.Driver.HandleReadCommands(device.Name, device.Protocols, reqs)
+		}
+		resultCh <- r
+	}()
+
+	select {
+	case r := <-resultCh:
+		return r.cvs, r.err
+	case <-ctx.Done():
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - readCommands: Driver didn't return in time for Device: %s, the late result will be dropped: %v", device.Name, ctx.Err()))
+		return nil, ctx.Err()
+	}
+}
+
+// writeCommands calls the ContextProtocolDriver if the Driver implements it,
+// otherwise it falls back to the ProtocolDriver. It returns the error of the
+// context if the context is done before the Driver returns.
+func writeCommands(ctx context.Context, device *contract.Device, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
+	errCh := make(chan error, 1)
+	go func() {
+		defer recoverDriverPanic(device.Name, func(err error) { errCh <- err })
+
+		if driver, ok := common.Driver.(dsModels.ContextProtocolDriver); ok {
+			errCh <- driver.HandleWriteCommandsWithContext(ctx, device.Name, device.Protocols, reqs, params)
+		} else {
+			errCh <- common.Driver.HandleWriteCommands(device.Name, device.Protocols, reqs, params)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		common.LoggingClient.Warn(fmt.Sprintf("Handler - writeCommands: Driver didn't return in time for Device: %s: %v", device.Name, ctx.Err()))
+		return ctx.Err()
+	}
+}
+
+// recoverDriverPanic converts a panic raised by the Driver into an error, since
+// the Driver is called in a separate goroutine which isn't covered by the
+// panic recovery of the HTTP server.
+func recoverDriverPanic(deviceName string, report func(err error)) {
+	if r := recover(); r != nil {
+		err := fmt.Errorf("Driver panic for Device %s: %v", deviceName, r)
+		common.LoggingClient.Error(err.Error())
+		report(err)
+	}
+}
+
+// newDriverError creates the AppError for an error returned by the Driver call,
+// a timeout is reported as 504 and a cancelled request as 503.
+func newDriverError(msg string, err error) common.AppError {
+	switch err {
+	case context.DeadlineExceeded:
+		return common.NewTimeoutError(msg, err)
+	case context.Canceled:
+		return common.NewServiceUnavailableError(msg, err)
+	default:
+		return common.NewServerError(msg, err)
+	}
+}
@@ -0,0 +1,112 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type contextDriverMock struct {
+	mock.DriverMock
+	ctx context.Context
+}
+
+func (d *contextDriverMock) HandleReadCommandsWithContext(ctx context.Context, deviceName string, protocols map[string]contract.ProtocolProperties, reqs []dsModels.CommandRequest) ([]*dsModels.CommandValue, error) {
+	d.ctx = ctx
+	return d.HandleReadCommands(deviceName, protocols, reqs)
+}
+
+func (d *contextDriverMock) HandleWriteCommandsWithContext(ctx context.Context, deviceName string, protocols map[string]contract.ProtocolProperties, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
+	d.ctx = ctx
+	return d.HandleWriteCommands(deviceName, protocols, reqs, params)
+}
+
+func TestContextProtocolDriver(t *testing.T) {
+	driver := &contextDriverMock{}
+	common.Driver = driver
+	common.CurrentConfig.Service.Timeout = 5000
+	defer func() {
+		common.Driver = &mock.DriverMock{}
+		common.CurrentConfig.Service.Timeout = 0
+	}()
+
+	correlationId := "4c5e2a3f-4f2b-4a34-94b2-2b4c2f6d7d1e"
+	ctx := context.WithValue(context.Background(), common.CorrelationHeader, correlationId)
+
+	tests := []struct {
+		testName string
+		vars     map[string]string
+		body     string
+		method   string
+	}{
+		{"Read", map[string]string{"name": "Random-UnsignedInteger-Generator01", "command": "RandomValue_Uint8"}, "", methodGet},
+		{"Write", map[string]string{"name": "Random-UnsignedInteger-Generator01", "command": "ResourceTestWrite_Uint8"}, `{"ResourceTestWrite_Uint8":"123"}`, methodSet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			driver.ctx = nil
+			_, appErr := CommandHandler(ctx, tt.vars, tt.body, clients.ContentTypeJSON, tt.method, "")
+			if appErr != nil {
+				t.Fatalf("%s unexpected error: %s", tt.testName, appErr.Message())
+			}
+			if driver.ctx == nil {
+				t.Fatalf("%s the context aware function of the Driver is not called", tt.testName)
+			}
+			assert.Equal(t, correlationId, driver.ctx.Value(common.CorrelationHeader))
+			_, ok := driver.ctx.Deadline()
+			assert.True(t, ok, "the context passed to the Driver should have a deadline")
+			assert.Error(t, driver.ctx.Err(), "the context passed to the Driver should be cancelled once the command completes")
+		})
+	}
+}
+
+type slowDriverMock struct {
+	mock.DriverMock
+}
+
+func (slowDriverMock) HandleReadCommands(deviceName string, protocols map[string]contract.ProtocolProperties, reqs []dsModels.CommandRequest) ([]*dsModels.CommandValue, error) {
+	time.Sleep(200 * time.Millisecond)
+	return nil, nil
+}
+
+func (slowDriverMock) HandleWriteCommands(deviceName string, protocols map[string]contract.ProtocolProperties, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
+	panic("the write command panics")
+}
+
+func TestDriverTimeout(t *testing.T) {
+	common.Driver = slowDriverMock{}
+	common.CurrentConfig.Service.Timeout = 20
+	defer func() {
+		common.Driver = &mock.DriverMock{}
+		common.CurrentConfig.Service.Timeout = 0
+	}()
+
+	vars := map[string]string{"name": "Random-UnsignedInteger-Generator01", "command": "RandomValue_Uint8"}
+	_, appErr := CommandHandler(context.Background(), vars, "", "", methodGet, "")
+	if appErr == nil {
+		t.Fatal("expected a timeout error")
+	}
+	assert.Equal(t, http.StatusGatewayTimeout, appErr.Code())
+
+	// a panic in the Driver is reported as an error
+	vars[common.CommandVar] = "ResourceTestWrite_Uint8"
+	_, appErr = CommandHandler(context.Background(), vars, `{"ResourceTestWrite_Uint8":"123"}`, clients.ContentTypeJSON, methodSet, "")
+	if appErr == nil {
+		t.Fatal("expected a server error")
+	}
+	assert.Equal(t, http.StatusInternalServerError, appErr.Code())
+}
@@ -0,0 +1,97 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/google/uuid"
+)
+
+// ExecuteInitCmd runs the [Device] InitCmd against a device which has just been
+// added to the Driver. The device is marked DISABLED if the command fails and
+// [Device] DisableOnInitCmdFailure is set.
+func ExecuteInitCmd(device contract.Device) common.AppError {
+	appErr := executeLifecycleCmd(device, common.CurrentConfig.Device.InitCmd, common.CurrentConfig.Device.InitCmdArgs)
+	if appErr == nil {
+		return nil
+	}
+
+	common.LoggingClient.Error(fmt.Sprintf("InitCmd %s failed for device %s: %s", common.CurrentConfig.Device.InitCmd, device.Name, appErr.Message()))
+	if common.CurrentConfig.Device.DisableOnInitCmdFailure {
+		disableDevice(device.Name)
+	}
+	return appErr
+}
+
+// StartInitCmd runs the [Device] InitCmd against the device in a new goroutine,
+// so that the caller, e.g. the callback answering Core Metadata, doesn't wait
+// for the device to answer.
+func StartInitCmd(device contract.Device) {
+	if common.CurrentConfig.Device.InitCmd == "" {
+		return
+	}
+	go ExecuteInitCmd(device)
+}
+
+// ExecuteRemoveCmd runs the [Device] RemoveCmd against a device which is about
+// to be removed from the Driver.
+func ExecuteRemoveCmd(device contract.Device) common.AppError {
+	appErr := executeLifecycleCmd(device, common.CurrentConfig.Device.RemoveCmd, common.CurrentConfig.Device.RemoveCmdArgs)
+	if appErr != nil {
+		common.LoggingClient.Error(fmt.Sprintf("RemoveCmd %s failed for device %s: %s", common.CurrentConfig.Device.RemoveCmd, device.Name, appErr.Message()))
+	}
+	return appErr
+}
+
+// executeLifecycleCmd writes args to the command of the device, or reads the
+// command if there are no args. Nothing is done if cmd is empty or the device
+// is locked.
+func executeLifecycleCmd(device contract.Device, cmd string, args string) common.AppError {
+	if cmd == "" {
+		return nil
+	}
+	if device.AdminState == contract.Locked {
+		common.LoggingClient.Info(fmt.Sprintf("Device %s is locked, skipping the command %s", device.Name, cmd))
+		return nil
+	}
+
+	method := http.MethodGet
+	if args != "" {
+		method = http.MethodPut
+	}
+	common.LoggingClient.Debug(fmt.Sprintf("Executing the command %s %s for device %s", method, cmd, device.Name))
+
+	ctx := context.WithValue(context.Background(), common.CorrelationHeader, uuid.New().String())
+	vars := map[string]string{common.NameVar: device.Name, common.CommandVar: cmd}
+	_, appErr := CommandHandler(ctx, vars, args, clients.ContentTypeJSON, method, "")
+	return appErr
+}
+
+func disableDevice(name string) {
+	device, ok := cache.Devices().ForName(name)
+	if !ok {
+		return
+	}
+	device.OperatingState = contract.Disabled
+	if err := cache.Devices().Update(device); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't disable device %s in the cache: %v", name, err))
+	}
+
+	ctx := context.WithValue(context.Background(), common.CorrelationHeader, uuid.New().String())
+	if err := common.DeviceClient.UpdateOpStateByName(name, contract.Disabled, ctx); err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("Couldn't disable device %s in Core Metadata: %v", name, err))
+		return
+	}
+	common.LoggingClient.Info(fmt.Sprintf("Disabled device %s", name))
+}
@@ -0,0 +1,101 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const lifecycleDevice = "Random-UnsignedInteger-Generator01"
+
+func TestExecuteLifecycleCmd(t *testing.T) {
+	device, ok := cache.Devices().ForName(lifecycleDevice)
+	if !ok {
+		t.Fatalf("device %s not found in the cache", lifecycleDevice)
+	}
+	locked := device
+	locked.AdminState = contract.Locked
+
+	tests := []struct {
+		testName  string
+		device    contract.Device
+		cmd       string
+		args      string
+		expectErr bool
+	}{
+		{"NoCmd", device, "", "", false},
+		{"Read", device, "RandomValue_Uint8", "", false},
+		{"Write", device, "EnableRandomization_Uint8", `{"EnableRandomization_Uint8":"true"}`, false},
+		{"ReadWriteOnly", device, "EnableRandomization_Uint8", "", true},
+		{"InvalidArgs", device, "EnableRandomization_Uint8", `{"EnableRandomization_Uint8":"maybe"}`, true},
+		{"CmdNotFound", device, "NoSuchCommand", "", true},
+		{"Locked", locked, "NoSuchCommand", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			appErr := executeLifecycleCmd(tt.device, tt.cmd, tt.args)
+			if !tt.expectErr && appErr != nil {
+				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, appErr.Error())
+			} else if tt.expectErr && appErr == nil {
+				t.Errorf("%s expectErr:%v no error thrown", tt.testName, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestExecuteInitCmdDisablesDevice(t *testing.T) {
+	device, _ := cache.Devices().ForName(lifecycleDevice)
+	deviceInfo := common.CurrentConfig.Device
+	defer func() {
+		common.CurrentConfig.Device = deviceInfo
+		_ = cache.Devices().Update(device)
+	}()
+
+	common.CurrentConfig.Device.InitCmd = "EnableRandomization_Uint8"
+	common.CurrentConfig.Device.DisableOnInitCmdFailure = false
+	assert.NotNil(t, ExecuteInitCmd(device))
+	d, _ := cache.Devices().ForName(lifecycleDevice)
+	assert.Equal(t, device.OperatingState, d.OperatingState)
+
+	common.CurrentConfig.Device.DisableOnInitCmdFailure = true
+	assert.NotNil(t, ExecuteInitCmd(device))
+	d, _ = cache.Devices().ForName(lifecycleDevice)
+	assert.Equal(t, contract.OperatingState(contract.Disabled), d.OperatingState)
+
+	_ = cache.Devices().Update(device)
+	common.CurrentConfig.Device.RemoveCmd = "EnableRandomization_Uint8"
+	common.CurrentConfig.Device.RemoveCmdArgs = `{"EnableRandomization_Uint8":"false"}`
+	assert.Nil(t, ExecuteRemoveCmd(device))
+}
+
+func TestStartInitCmd(t *testing.T) {
+	device, _ := cache.Devices().ForName(lifecycleDevice)
+	deviceInfo := common.CurrentConfig.Device
+	defer func() {
+		common.CurrentConfig.Device = deviceInfo
+		_ = cache.Devices().Update(device)
+	}()
+
+	// the InitCmd fails and disables the device in the background
+	common.CurrentConfig.Device.InitCmd = "EnableRandomization_Uint8"
+	common.CurrentConfig.Device.DisableOnInitCmdFailure = true
+	StartInitCmd(device)
+
+	disabled := false
+	for i := 0; i < 100 && !disabled; i++ {
+		d, _ := cache.Devices().ForName(lifecycleDevice)
+		disabled = d.OperatingState == contract.Disabled
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, disabled, "the InitCmd didn't run")
+}
@@ -0,0 +1,60 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// mergeMaskedValues reads the current values of the DeviceResources whose
+// mask only covers some bits of the written values, and merges the transformed
+// values into them so that the bits outside of the masks are written back as
+// they are. The values of drs, reqs and cvs are those of the same resources.
+func mergeMaskedValues(ctx context.Context, device *contract.Device, drs []contract.DeviceResource, reqs []dsModels.CommandRequest, cvs []*dsModels.CommandValue) common.AppError {
+	var readReqs []dsModels.CommandRequest
+	var masked []int
+	for i, cv := range cvs {
+		if !transformer.MaskedWrite(cv, drs[i].Properties.Value) {
+			continue
+		}
+		if err := checkReadable(&drs[i]); err != nil {
+			msg := fmt.Sprintf("Handler - mergeMaskedValues: the masked bits of DeviceResource %s cannot be written: %v", drs[i].Name, err)
+			common.LoggingClient.Error(msg)
+			return common.NewBadRequestError(msg, err)
+		}
+		readReqs = append(readReqs, reqs[i])
+		masked = append(masked, i)
+	}
+	if len(masked) == 0 {
+		return nil
+	}
+
+	current, err := readCommands(ctx, device, readReqs)
+	if err == nil && len(current) != len(readReqs) {
+		err = fmt.Errorf("the Driver returned %d values for %d DeviceResources", len(current), len(readReqs))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Handler - mergeMaskedValues: reading the current values failed for Device: %s, %v", device.Name, err)
+		common.LoggingClient.Error(msg)
+		return newDriverError(msg, err)
+	}
+
+	for j, i := range masked {
+		if err = transformer.MergeMaskedValue(cvs[i], current[j], drs[i].Properties.Value); err != nil {
+			msg := fmt.Sprintf("Handler - mergeMaskedValues: CommandValue (%s) merge failed: %v", cvs[i].String(), err)
+			common.LoggingClient.Error(msg)
+			return common.NewServerError(msg, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMaskedResource(name string, readWrite string) contract.DeviceResource {
+	return contract.DeviceResource{
+		Name: name,
+		Properties: contract.ProfileProperty{
+			Value: contract.PropertyValue{Type: "Uint8", ReadWrite: readWrite, Mask: "240", Shift: "-4"},
+		},
+	}
+}
+
+func TestMergeMaskedValues(t *testing.T) {
+	device, ok := cache.Devices().ForName("Random-UnsignedInteger-Generator01")
+	if !ok {
+		t.Fatal("the mock device should be in the cache")
+	}
+
+	tests := []struct {
+		testName string
+		dr       contract.DeviceResource
+		value    uint8
+		expected uint8
+		code     int
+	}{
+		// the mock Driver reads 123 (0x7B), the low bits are kept
+		{"Merge", newMaskedResource("RandomValue_Uint8", "RW"), 0xA0, 0xAB, 0},
+		{"WriteOnly", newMaskedResource("RandomValue_Uint8", "W"), 0xA0, 0xA0, http.StatusBadRequest},
+		{"ReadFailure", newMaskedResource("Error", "RW"), 0xA0, 0xA0, http.StatusInternalServerError},
+		{"NotMasked", contract.DeviceResource{Name: "Error"}, 0xA0, 0xA0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cv, _ := dsModels.NewUint8Value(tt.dr.Name, 0, tt.value)
+			reqs := []dsModels.CommandRequest{{DeviceResourceName: tt.dr.Name, Type: dsModels.Uint8}}
+
+			appErr := mergeMaskedValues(context.Background(), &device, []contract.DeviceResource{tt.dr}, reqs, []*dsModels.CommandValue{cv})
+			if tt.code == 0 {
+				assert.Nil(t, appErr)
+			} else if assert.NotNil(t, appErr) {
+				assert.Equal(t, tt.code, appErr.Code())
+			}
+			v, _ := cv.Uint8Value()
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// Query parameters of the transformData debug request.
+const (
+	transformDeviceParam  = "device"
+	transformProfileParam = "profile"
+	transformValueParam   = "value"
+	transformMethodParam  = "method"
+)
+
+// TransformStep is the value of a DeviceResource after a step of the transforms.
+type TransformStep struct {
+	Step    string `json:"step"`
+	Value   string `json:"value,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"`
+}
+
+// TransformResult is the result of the transformData debug request.
+type TransformResult struct {
+	Profile  string          `json:"profile"`
+	Resource string          `json:"resource"`
+	Method   string          `json:"method"`
+	Steps    []TransformStep `json:"steps"`
+}
+
+// TransformHandler evaluates the transforms of a DeviceResource on a value
+// without calling the Driver. The query gives the device or the profile of the
+// DeviceResource, the value, and the method: "get" (the default) evaluates a
+// raw reading through the read transforms, the assertion and the mappings,
+// "set" evaluates a write parameter through the mappings, the range check and
+// the inverse transforms. The assertion doesn't change the state of the device.
+func TransformHandler(vars map[string]string, queryParams string) (*TransformResult, common.AppError) {
+	common.LoggingClient.Info(fmt.Sprintf("service: transform request: resource: %s, query: %s", vars[common.ResourceVar], queryParams))
+
+	query, err := url.ParseQuery(queryParams)
+	if err != nil {
+		msg := fmt.Sprintf("invalid query %s: %v", queryParams, err)
+		common.LoggingClient.Error(msg)
+		return nil, common.NewBadRequestError(msg, err)
+	}
+
+	profileName := query.Get(transformProfileParam)
+	if deviceName := query.Get(transformDeviceParam); deviceName != "" {
+		device, ok := cache.Devices().ForName(deviceName)
+		if !ok {
+			msg := fmt.Sprintf("Device: %s not found", deviceName)
+			common.LoggingClient.Error(msg)
+			return nil, common.NewNotFoundError(msg, nil)
+		}
+		profileName = device.Profile.Name
+	} else if profileName == "" {
+		msg := fmt.Sprintf("the %s or the %s query parameter is required", transformDeviceParam, transformProfileParam)
+		common.LoggingClient.Error(msg)
+		return nil, common.NewBadRequestError(msg, nil)
+	}
+
+	dr, ok := cache.Profiles().DeviceResource(profileName, vars[common.ResourceVar])
+	if !ok {
+		msg := fmt.Sprintf("DeviceResource: %s not found in DeviceProfile: %s", vars[common.ResourceVar], profileName)
+		common.LoggingClient.Error(msg)
+		return nil, common.NewNotFoundError(msg, nil)
+	}
+
+	value, ok := query[transformValueParam]
+	if !ok {
+		msg := fmt.Sprintf("the %s query parameter is required", transformValueParam)
+		common.LoggingClient.Error(msg)
+		return nil, common.NewBadRequestError(msg, nil)
+	}
+
+	result := &TransformResult{Profile: profileName, Resource: dr.Name, Method: common.GetCmdMethod}
+	switch strings.ToLower(query.Get(transformMethodParam)) {
+	case "", common.GetCmdMethod:
+		err = transformRead(result, profileName, &dr, value[0])
+	case common.SetCmdMethod:
+		result.Method = common.SetCmdMethod
+		err = transformWrite(result, profileName, &dr, value[0])
+	default:
+		err = fmt.Errorf("the %s query parameter should be %s or %s", transformMethodParam, common.GetCmdMethod, common.SetCmdMethod)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("service: transform request: %v", err)
+		common.LoggingClient.Error(msg)
+		return nil, common.NewBadRequestError(msg, err)
+	}
+	return result, nil
+}
+
+// transformRead adds the read steps of the raw value to the result, an error
+// is returned if the value can't be parsed to the type of the DeviceResource.
+func transformRead(result *TransformResult, profileName string, dr *contract.DeviceResource, value string) error {
+	cv, err := createCommandValueFromDR(dr, value)
+	if err != nil {
+		return err
+	}
+	result.addStep("raw", cv, nil)
+
+	if common.CurrentConfig.Device.DataTransform {
+		err = transformer.TransformReadResource(cv, dr)
+		result.addStep("transform", cv, err)
+		if err != nil {
+			return nil
+		}
+	} else {
+		result.skipStep("transform", cv)
+	}
+
+	if assertion := dr.Properties.Value.Assertion; assertion != "" {
+		err = transformer.EvaluateAssertion(cv, assertion)
+		if err != nil {
+			err = fmt.Errorf("%v, the assertion policy is %s", err, transformer.AssertionPolicy(dr))
+		}
+		result.addStep("assertion", cv, err)
+	}
+
+	ro, err := cache.Profiles().ResourceOperation(profileName, dr.Name, common.GetCmdMethod)
+	if err == nil && len(ro.Mappings) > 0 {
+		mapped, ok := transformer.MapCommandValue(cv, ro.Mappings)
+		if !ok {
+			result.addStep("mapping", cv, fmt.Errorf("no mapping matches the value %s", cv.ValueToString()))
+		} else {
+			result.addStep("mapping", mapped, nil)
+		}
+	}
+	return nil
+}
+
+// transformWrite adds the write steps of the parameter to the result, an error
+// is returned if the parameter can't be parsed to the type of the DeviceResource.
+func transformWrite(result *TransformResult, profileName string, dr *contract.DeviceResource, value string) error {
+	result.Steps = append(result.Steps, TransformStep{Step: "parameter", Value: value})
+
+	ro, err := cache.Profiles().ResourceOperation(profileName, dr.Name, common.SetCmdMethod)
+	if err == nil && len(ro.Mappings) > 0 {
+		step := TransformStep{Step: "mapping", Value: value}
+		if mapped, ok := ro.Mappings[value]; ok {
+			value = mapped
+			step.Value = mapped
+		} else {
+			step.Error = fmt.Sprintf("no mapping matches the parameter %s", value)
+		}
+		result.Steps = append(result.Steps, step)
+	}
+
+	cv, err := createCommandValueFromDR(dr, value)
+	if err != nil {
+		return err
+	}
+
+	if dr.Properties.Value.Minimum != "" || dr.Properties.Value.Maximum != "" {
+		err = checkValueRange(cv, dr)
+		result.addStep("range", cv, err)
+		if err != nil {
+			return nil
+		}
+	}
+
+	if common.CurrentConfig.Device.DataTransform {
+		err = transformer.TransformWriteResource(cv, dr)
+		result.addStep("transform", cv, err)
+	} else {
+		result.skipStep("transform", cv)
+	}
+	return nil
+}
+
+func (r *TransformResult) addStep(step string, cv *dsModels.CommandValue, err error) {
+	s := TransformStep{Step: step, Value: transformStepValue(cv)}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	r.Steps = append(r.Steps, s)
+}
+
+func (r *TransformResult) skipStep(step string, cv *dsModels.CommandValue) {
+	r.Steps = append(r.Steps, TransformStep{Step: step, Value: transformStepValue(cv), Skipped: true})
+}
+
+// transformStepValue returns the value of cv, the floats are written in
+// E notation rather than base64 so that they are readable.
+func transformStepValue(cv *dsModels.CommandValue) string {
+	return cv.ValueToString(contract.ENotation)
+}
@@ -0,0 +1,93 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformHandler(t *testing.T) {
+	profile := "profile=" + mock.ProfileInt
+	tests := []struct {
+		testName    string
+		resource    string
+		queryParams string
+		steps       []TransformStep
+	}{
+		{"ReadTransform", "ResourceTestTransform_Offset", profile + "&value=5",
+			[]TransformStep{{Step: "raw", Value: "5"}, {Step: "transform", Value: "6"}}},
+		{"ReadByDevice", "ResourceTestTransform_Offset", "device=" + deviceIntegerGenerator.Name + "&value=5",
+			[]TransformStep{{Step: "raw", Value: "5"}, {Step: "transform", Value: "6"}}},
+		{"ReadTransformFailure", "ResourceTestTransform_Fail", profile + "&value=5",
+			[]TransformStep{{Step: "raw", Value: "5"}, {Step: "transform", Value: "5", Error: `strconv.ParseInt: parsing "error": invalid syntax`}}},
+		{"ReadAssertion", "ResourceTestAssertion_Pass", profile + "&value=123",
+			[]TransformStep{{Step: "raw", Value: "123"}, {Step: "transform", Value: "123"}, {Step: "assertion", Value: "123"}}},
+		{"ReadAssertionFailure", "ResourceTestAssertion_Pass", profile + "&value=5",
+			[]TransformStep{{Step: "raw", Value: "5"}, {Step: "transform", Value: "5"},
+				{Step: "assertion", Value: "5", Error: "assertion (123) failed with value: 5, the assertion policy is disable"}}},
+		{"ReadMapping", "ResourceTestMapping_Pass", profile + "&value=123&method=GET",
+			[]TransformStep{{Step: "raw", Value: "123"}, {Step: "transform", Value: "123"}, {Step: "mapping", Value: "Pass"}}},
+		{"WriteMapping", "ResourceTestMapping_Pass", profile + "&value=Pass&method=set",
+			[]TransformStep{{Step: "parameter", Value: "Pass"}, {Step: "mapping", Value: "123"}, {Step: "transform", Value: "123"}}},
+		{"WriteTransform", "ResourceTestTransform_Offset", profile + "&value=6&method=set",
+			[]TransformStep{{Step: "parameter", Value: "6"}, {Step: "transform", Value: "5"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			result, appErr := TransformHandler(map[string]string{common.ResourceVar: tt.resource}, tt.queryParams)
+			if appErr != nil {
+				t.Fatalf("unexpected error: %v", appErr.Error())
+			}
+			assert.Equal(t, mock.ProfileInt, result.Profile)
+			assert.Equal(t, tt.resource, result.Resource)
+			assert.Equal(t, tt.steps, result.Steps)
+		})
+	}
+}
+
+func TestTransformHandlerSkipsTransform(t *testing.T) {
+	common.CurrentConfig.Device.DataTransform = false
+	defer func() {
+		common.CurrentConfig.Device.DataTransform = true
+	}()
+
+	result, appErr := TransformHandler(map[string]string{common.ResourceVar: "ResourceTestTransform_Offset"}, "profile="+mock.ProfileInt+"&value=5")
+	if assert.Nil(t, appErr) {
+		assert.Equal(t, []TransformStep{{Step: "raw", Value: "5"}, {Step: "transform", Value: "5", Skipped: true}}, result.Steps)
+	}
+}
+
+func TestTransformHandlerErrors(t *testing.T) {
+	profile := "profile=" + mock.ProfileInt
+	tests := []struct {
+		testName    string
+		resource    string
+		queryParams string
+		code        int
+	}{
+		{"NoDeviceOrProfile", "ResourceTestTransform_Pass", "value=5", http.StatusBadRequest},
+		{"DeviceNotFound", "ResourceTestTransform_Pass", "device=NoSuchDevice&value=5", http.StatusNotFound},
+		{"ResourceNotFound", "NoSuchResource", profile + "&value=5", http.StatusNotFound},
+		{"NoValue", "ResourceTestTransform_Pass", profile, http.StatusBadRequest},
+		{"InvalidValue", "ResourceTestTransform_Pass", profile + "&value=abc", http.StatusBadRequest},
+		{"InvalidMethod", "ResourceTestTransform_Pass", profile + "&value=5&method=post", http.StatusBadRequest},
+		{"InvalidQuery", "ResourceTestTransform_Pass", "%zz", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, appErr := TransformHandler(map[string]string{common.ResourceVar: tt.resource}, tt.queryParams)
+			if assert.NotNil(t, appErr) {
+				assert.Equal(t, tt.code, appErr.Code())
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/transformer"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+const (
+	readOnly  = "R"
+	writeOnly = "W"
+)
+
+// valueRanges are the names and the ranges of the numeric ValueTypes used in
+// the errors of the values which overflow their type.
+var valueRanges = map[dsModels.ValueType][3]string{
+	dsModels.Uint8:   {"Uint8", "0", "255"},
+	dsModels.Uint16:  {"Uint16", "0", "65535"},
+	dsModels.Uint32:  {"Uint32", "0", "4294967295"},
+	dsModels.Uint64:  {"Uint64", "0", "18446744073709551615"},
+	dsModels.Int8:    {"Int8", "-128", "127"},
+	dsModels.Int16:   {"Int16", "-32768", "32767"},
+	dsModels.Int32:   {"Int32", "-2147483648", "2147483647"},
+	dsModels.Int64:   {"Int64", "-9223372036854775808", "9223372036854775807"},
+	dsModels.Float32: {"Float32", "-3.4028234663852886e+38", "3.4028234663852886e+38"},
+	dsModels.Float64: {"Float64", "-1.7976931348623157e+308", "1.7976931348623157e+308"},
+}
+
+// outOfRangeError replaces the range error returned by strconv when parsing
+// the value v to the type t with an error giving the range of t.
+func outOfRangeError(t dsModels.ValueType, v string, err error) error {
+	numErr, ok := err.(*strconv.NumError)
+	if !ok || numErr.Err != strconv.ErrRange {
+		return err
+	}
+	r, ok := valueRanges[t]
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("value %s overflows %s, the value should be between %s and %s", v, r[0], r[1], r[2])
+}
+
+// checkReadable returns an error if the DeviceResource is write-only.
+func checkReadable(dr *contract.DeviceResource) error {
+	if strings.ToUpper(strings.TrimSpace(dr.Properties.Value.ReadWrite)) == writeOnly {
+		return fmt.Errorf("DeviceResource %s is write-only (readWrite: %s), it cannot be read", dr.Name, dr.Properties.Value.ReadWrite)
+	}
+	return nil
+}
+
+// checkWritable returns an error if the DeviceResource is read-only. A
+// DeviceResource without readWrite property is readable and writable.
+func checkWritable(dr *contract.DeviceResource) error {
+	if strings.ToUpper(strings.TrimSpace(dr.Properties.Value.ReadWrite)) == readOnly {
+		return fmt.Errorf("DeviceResource %s is read-only (readWrite: %s), it cannot be written", dr.Name, dr.Properties.Value.ReadWrite)
+	}
+	return nil
+}
+
+// checkValueRange returns an error if the numeric value of cv, or an element of
+// a numeric array, is less than the Minimum or greater than the Maximum of the
+// DeviceResource.
+func checkValueRange(cv *dsModels.CommandValue, dr *contract.DeviceResource) error {
+	pv := dr.Properties.Value
+	if pv.Minimum == "" && pv.Maximum == "" {
+		return nil
+	}
+
+	values, isArray, err := transformer.NumericArrayValues(cv)
+	if err != nil {
+		return err
+	}
+	if !isArray {
+		v, ok, err := transformer.NumericValue(cv)
+		if err != nil || !ok {
+			return err
+		}
+		values = []*big.Float{v}
+	}
+
+	if pv.Minimum != "" {
+		min, _, err := big.ParseFloat(strings.TrimSpace(pv.Minimum), 10, 128, big.ToNearestEven)
+		if err != nil {
+			return fmt.Errorf("the minimum %s of DeviceResource %s is not a number", pv.Minimum, dr.Name)
+		}
+		for i, v := range values {
+			if v.Cmp(min) < 0 {
+				return fmt.Errorf("%s of DeviceResource %s is less than the minimum %s", describeValue(cv, v, i, isArray), dr.Name, pv.Minimum)
+			}
+		}
+	}
+	if pv.Maximum != "" {
+		max, _, err := big.ParseFloat(strings.TrimSpace(pv.Maximum), 10, 128, big.ToNearestEven)
+		if err != nil {
+			return fmt.Errorf("the maximum %s of DeviceResource %s is not a number", pv.Maximum, dr.Name)
+		}
+		for i, v := range values {
+			if v.Cmp(max) > 0 {
+				return fmt.Errorf("%s of DeviceResource %s is greater than the maximum %s", describeValue(cv, v, i, isArray), dr.Name, pv.Maximum)
+			}
+		}
+	}
+	return nil
+}
+
+func describeValue(cv *dsModels.CommandValue, v *big.Float, i int, isArray bool) string {
+	if isArray {
+		return fmt.Sprintf("element %d %s", i, v.Text('g', -1))
+	}
+	return fmt.Sprintf("value %s", cv.ValueToString())
+}
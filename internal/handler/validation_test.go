@@ -0,0 +1,118 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+func newDeviceResource(readWrite string, min string, max string) *contract.DeviceResource {
+	return &contract.DeviceResource{
+		Name: "resource",
+		Properties: contract.ProfileProperty{
+			Value: contract.PropertyValue{ReadWrite: readWrite, Minimum: min, Maximum: max},
+		},
+	}
+}
+
+func TestCheckReadWrite(t *testing.T) {
+	tests := []struct {
+		readWrite    string
+		readableErr  bool
+		writeableErr bool
+	}{
+		{"R", false, true},
+		{"W", true, false},
+		{"RW", false, false},
+		{"rw", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.readWrite, func(t *testing.T) {
+			dr := newDeviceResource(tt.readWrite, "", "")
+			if err := checkReadable(dr); (err != nil) != tt.readableErr {
+				t.Errorf("checkReadable expectErr:%v error:%v", tt.readableErr, err)
+			}
+			if err := checkWritable(dr); (err != nil) != tt.writeableErr {
+				t.Errorf("checkWritable expectErr:%v error:%v", tt.writeableErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckValueRange(t *testing.T) {
+	int8Value, _ := dsModels.NewInt8Value("resource", 0, -10)
+	uint8Value, _ := dsModels.NewUint8Value("resource", 0, 200)
+	int64Value, _ := dsModels.NewInt64Value("resource", 0, math.MaxInt64)
+	uint64Value, _ := dsModels.NewUint64Value("resource", 0, math.MaxUint64)
+	float32Value, _ := dsModels.NewFloat32Value("resource", 0, 1.5)
+	float64Value, _ := dsModels.NewFloat64Value("resource", 0, 1.0000001)
+	nanValue, _ := dsModels.NewFloat64Value("resource", 0, math.NaN())
+	boolValue, _ := dsModels.NewBoolValue("resource", 0, true)
+	int16Array, _ := dsModels.NewInt16ArrayValue("resource", 0, []int16{-5, 0, 5})
+	uint8Array, _ := dsModels.NewUint8ArrayValue("resource", 0, []uint8{1, 200, 3})
+	float32Array, _ := dsModels.NewFloat32ArrayValue("resource", 0, []float32{0.5, 1.5})
+	nanArray, _ := dsModels.NewFloat32ArrayValue("resource", 0, []float32{0.5, float32(math.NaN())})
+	emptyArray, _ := dsModels.NewInt16ArrayValue("resource", 0, []int16{})
+
+	tests := []struct {
+		testName  string
+		cv        *dsModels.CommandValue
+		min       string
+		max       string
+		expectErr bool
+	}{
+		{"NoLimits", int8Value, "", "", false},
+		{"Int8InRange", int8Value, "-10", "10", false},
+		{"Int8BelowMinimum", int8Value, "-9", "", true},
+		{"Uint8AboveMaximum", uint8Value, "", "199", true},
+		{"Uint8InRange", uint8Value, "0", "255", false},
+		{"Int64AtMaximum", int64Value, "", "9223372036854775807", false},
+		{"Int64AboveMaximum", int64Value, "", "9223372036854775806", true},
+		{"Uint64AtMaximum", uint64Value, "0", "18446744073709551615", false},
+		{"Uint64AboveMaximum", uint64Value, "", "18446744073709551614", true},
+		{"Float32InRange", float32Value, "1.5", "1.5", false},
+		{"Float64AboveMaximum", float64Value, "", "1.0", true},
+		{"NaN", nanValue, "0", "1", true},
+		{"InvalidMinimum", int8Value, "min", "", true},
+		{"NotNumeric", boolValue, "0", "0", false},
+		{"ArrayInRange", int16Array, "-5", "5", false},
+		{"ArrayElementBelowMinimum", int16Array, "-4", "", true},
+		{"ArrayElementAboveMaximum", uint8Array, "0", "199", true},
+		{"FloatArrayElementAboveMaximum", float32Array, "", "1", true},
+		{"ArrayNaN", nanArray, "0", "1", true},
+		{"EmptyArray", emptyArray, "0", "1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := checkValueRange(tt.cv, newDeviceResource("RW", tt.min, tt.max))
+			if (err != nil) != tt.expectErr {
+				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestReadWriteValidation(t *testing.T) {
+	vars := map[string]string{"name": "Random-UnsignedInteger-Generator01", "command": "EnableRandomization_Uint8"}
+	if _, appErr := CommandHandler(context.Background(), vars, "", "", methodGet, ""); appErr == nil || appErr.Code() != http.StatusBadRequest {
+		t.Errorf("expected a bad request error reading a write-only DeviceResource, got %v", appErr)
+	}
+
+	params := `{"RandomValue_Float64":"1.0","EnableRandomization_Float64":"false"}`
+	if appErr := execWriteCmd(context.Background(), &mock.ValidDeviceRandomFloatGenerator, "RandomValue_Float64", params, clients.ContentTypeJSON); appErr == nil || appErr.Code() != http.StatusBadRequest {
+		t.Errorf("expected a bad request error writing a read-only DeviceResource, got %v", appErr)
+	}
+}
@@ -0,0 +1,123 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mock
+
+import (
+	"net"
+	"sync"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+// MqttBrokerMock is a stand-in MQTT broker listening on a local port, it
+// acknowledges the connections and records the messages published to it.
+type MqttBrokerMock struct {
+	// Messages receives every message published to the broker
+	Messages chan *packets.PublishPacket
+	// Connections receives the CONNECT packet of every accepted connection
+	Connections chan *packets.ConnectPacket
+
+	listener net.Listener
+	mutex    sync.Mutex
+	conns    []net.Conn
+}
+
+// NewMqttBrokerMock starts a broker listening on a random port of the loopback interface.
+func NewMqttBrokerMock() (*MqttBrokerMock, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &MqttBrokerMock{
+		Messages:    make(chan *packets.PublishPacket, 64),
+		Connections: make(chan *packets.ConnectPacket, 8),
+		listener:    listener,
+	}
+	go b.accept()
+	return b, nil
+}
+
+// Host returns the address the broker is listening on.
+func (b *MqttBrokerMock) Host() string {
+	return b.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the broker is listening on.
+func (b *MqttBrokerMock) Port() int {
+	return b.listener.Addr().(*net.TCPAddr).Port
+}
+
+// DropConnections closes the connections of all clients, the broker keeps listening.
+func (b *MqttBrokerMock) DropConnections() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+	b.conns = nil
+}
+
+// Close stops the broker and closes the connections of all clients.
+func (b *MqttBrokerMock) Close() {
+	b.listener.Close()
+	b.DropConnections()
+}
+
+func (b *MqttBrokerMock) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mutex.Lock()
+		b.conns = append(b.conns, conn)
+		b.mutex.Unlock()
+		go b.serve(conn)
+	}
+}
+
+func (b *MqttBrokerMock) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			b.Connections <- p
+			err = packets.NewControlPacket(packets.Connack).Write(conn)
+		case *packets.PublishPacket:
+			b.Messages <- p
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				err = ack.Write(conn)
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				err = rec.Write(conn)
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			err = comp.Write(conn)
+		case *packets.PingreqPacket:
+			err = packets.NewControlPacket(packets.Pingresp).Write(conn)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+)
+
+const (
+	defaultMaxBatchSize = 100
+	defaultMaxBatchWait = 100 * time.Millisecond
+)
+
+// batchEventPublisher is implemented by the EventPublisher which can send a
+// batch of JSON encoded events at once. Each event of the batch comes with
+// the context it was published with, PublishBatch returns the error of
+// publishing each event, nil for the events which were delivered.
+type batchEventPublisher interface {
+	PublishBatch(batch []batchItem) []error
+}
+
+// batchItem is an event waiting for its batch to be sent, with the context
+// carrying its correlation ID and content type.
+type batchItem struct {
+	ctx   context.Context
+	event *dsModels.Event
+	done  chan error
+}
+
+// batchPublisher wraps an EventPublisher and groups the JSON encoded events
+// into batches. Publish blocks until the batch of the event is sent, so the
+// caller still gets the result of publishing its event. The CBOR encoded
+// events are published one by one.
+type batchPublisher struct {
+	next    common.EventPublisher
+	maxSize int
+	maxWait time.Duration
+
+	mutex   sync.Mutex
+	pending []batchItem
+	timer   *time.Timer
+}
+
+func newBatchPublisher(next common.EventPublisher, config common.BatchInfo) (*batchPublisher, error) {
+	p := &batchPublisher{next: next, maxSize: config.MaxBatchSize, maxWait: defaultMaxBatchWait}
+	if p.maxSize <= 0 {
+		p.maxSize = defaultMaxBatchSize
+	}
+	if config.MaxBatchWait != "" {
+		d, err := time.ParseDuration(config.MaxBatchWait)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("the MaxBatchWait %s of the EventPublisher should be a positive duration", config.MaxBatchWait)
+		}
+		p.maxWait = d
+	}
+	return p, nil
+}
+
+func (p *batchPublisher) Publish(ctx context.Context, event *dsModels.Event) error {
+	if clients.FromContext(clients.ContentType, ctx) == clients.ContentTypeCBOR {
+		return p.next.Publish(ctx, event)
+	}
+
+	item := batchItem{ctx: ctx, event: event, done: make(chan error, 1)}
+	p.mutex.Lock()
+	p.pending = append(p.pending, item)
+	var batch []batchItem
+	if len(p.pending) >= p.maxSize {
+		batch = p.take()
+	} else if p.timer == nil {
+		p.timer = time.AfterFunc(p.maxWait, p.flush)
+	}
+	p.mutex.Unlock()
+
+	if batch != nil {
+		p.send(batch)
+	}
+	return <-item.done
+}
+
+// take removes the pending events and stops the timer, the caller must hold the mutex.
+func (p *batchPublisher) take() []batchItem {
+	batch := p.pending
+	p.pending = nil
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	return batch
+}
+
+// flush sends the pending events when MaxBatchWait elapsed.
+func (p *batchPublisher) flush() {
+	p.mutex.Lock()
+	batch := p.take()
+	p.mutex.Unlock()
+
+	if len(batch) > 0 {
+		p.send(batch)
+	}
+}
+
+// send publishes the batch at once if the wrapped EventPublisher supports
+// batches, otherwise the events are published one by one.
+func (p *batchPublisher) send(batch []batchItem) {
+	if bp, ok := p.next.(batchEventPublisher); ok {
+		errs := bp.PublishBatch(batch)
+		for i, item := range batch {
+			item.done <- errs[i]
+		}
+		return
+	}
+
+	for _, item := range batch {
+		item.done <- p.next.Publish(item.ctx, item.event)
+	}
+}
+
+// Close sends the pending events and closes the wrapped EventPublisher.
+func (p *batchPublisher) Close() error {
+	p.flush()
+	return p.next.Close()
+}
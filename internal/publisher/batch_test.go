@@ -0,0 +1,208 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+)
+
+// batchPublisherMock records the size of every batch it receives and the
+// correlation ID and content type of every event by payload, the events whose
+// payload is fail aren't delivered.
+type batchPublisherMock struct {
+	publisherMock
+	batches []int
+	headers map[string]string
+	fail    string
+}
+
+func (p *batchPublisherMock) PublishBatch(batch []batchItem) []error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.batches = append(p.batches, len(batch))
+	if p.headers == nil {
+		p.headers = make(map[string]string)
+	}
+	errs := make([]error, len(batch))
+	for i, item := range batch {
+		payload := string(item.event.EncodedEvent)
+		p.headers[payload] = clients.FromContext(clients.CorrelationHeader, item.ctx) + " " + clients.FromContext(clients.ContentType, item.ctx)
+		if p.fail != "" && payload == p.fail {
+			errs[i] = fmt.Errorf("event %s not delivered", payload)
+		}
+	}
+	return errs
+}
+
+func newBatchItems(events ...*dsModels.Event) []batchItem {
+	batch := make([]batchItem, len(events))
+	for i, event := range events {
+		batch[i] = batchItem{ctx: context.Background(), event: event}
+	}
+	return batch
+}
+
+func publishConcurrently(t *testing.T, p common.EventPublisher, n int, contentType string) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := publishEvent(p, "device1", "{}", contentType); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBatchPublisher(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          common.BatchInfo
+		events          int
+		contentType     string
+		expectedBatches int
+		expectedSingle  int
+	}{
+		{"max batch size", common.BatchInfo{MaxBatchSize: 3, MaxBatchWait: "1h"}, 6, clients.ContentTypeJSON, 2, 0},
+		{"max batch wait", common.BatchInfo{MaxBatchSize: 100, MaxBatchWait: "200ms"}, 5, clients.ContentTypeJSON, 1, 0},
+		{"CBOR events", common.BatchInfo{MaxBatchSize: 2, MaxBatchWait: "1h"}, 3, clients.ContentTypeCBOR, 0, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &batchPublisherMock{}
+			p, err := newBatchPublisher(next, tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publishConcurrently(t, p, tt.events, tt.contentType)
+
+			if len(next.batches) != tt.expectedBatches || len(next.published) != tt.expectedSingle {
+				t.Errorf("expected %d batches and %d single events, got batches %v and %d single events",
+					tt.expectedBatches, tt.expectedSingle, next.batches, len(next.published))
+			}
+		})
+	}
+}
+
+func TestBatchPublisherPerEventResults(t *testing.T) {
+	next := &batchPublisherMock{fail: "bad"}
+	p, err := newBatchPublisher(next, common.BatchInfo{MaxBatchSize: 3, MaxBatchWait: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := []string{"first", "bad", "last"}
+	errs := make([]error, len(payloads))
+	var wg sync.WaitGroup
+	for i, payload := range payloads {
+		wg.Add(1)
+		go func(i int, payload string) {
+			defer wg.Done()
+			errs[i] = publishEvent(p, "device1", payload, clients.ContentTypeJSON)
+		}(i, payload)
+	}
+	wg.Wait()
+
+	if len(next.batches) != 1 {
+		t.Fatalf("expected a single batch, got %v", next.batches)
+	}
+	for i, payload := range payloads {
+		if failed := errs[i] != nil; failed != (payload == "bad") {
+			t.Errorf("unexpected result %v of the event %s", errs[i], payload)
+		}
+	}
+}
+
+func TestBatchPublisherEventContexts(t *testing.T) {
+	next := &batchPublisherMock{}
+	p, err := newBatchPublisher(next, common.BatchInfo{MaxBatchSize: 2, MaxBatchWait: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the events of a batch keep their own correlation ID and content type
+	headers := map[string][2]string{
+		"first":  {"correlation-1", clients.ContentTypeJSON},
+		"second": {"correlation-2", clients.ContentTypeJSON + "; charset=utf-8"},
+	}
+	var wg sync.WaitGroup
+	for payload, h := range headers {
+		wg.Add(1)
+		go func(payload string, correlation string, contentType string) {
+			defer wg.Done()
+			ctx := context.WithValue(context.Background(), common.CorrelationHeader, correlation)
+			ctx = context.WithValue(ctx, clients.ContentType, contentType)
+			if err := p.Publish(ctx, newTestEvent(payload)); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		}(payload, h[0], h[1])
+	}
+	wg.Wait()
+
+	if len(next.batches) != 1 {
+		t.Fatalf("expected a single batch, got %v", next.batches)
+	}
+	for payload, h := range headers {
+		if expected := h[0] + " " + h[1]; next.headers[payload] != expected {
+			t.Errorf("expected the event %s to be sent with %s, got %s", payload, expected, next.headers[payload])
+		}
+	}
+}
+
+func TestBatchPublisherFallback(t *testing.T) {
+	next := &publisherMock{}
+	p, err := newBatchPublisher(next, common.BatchInfo{MaxBatchSize: 2, MaxBatchWait: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishConcurrently(t, p, 4, clients.ContentTypeJSON)
+
+	if len(next.result()) != 4 {
+		t.Errorf("expected 4 events published one by one, got %v", next.result())
+	}
+}
+
+func TestMQTTPublishBatch(t *testing.T) {
+	broker, err := mock.NewMqttBrokerMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	config := common.EventPublisherInfo{Type: TypeMQTT, Host: broker.Host(), Port: broker.Port(), Topic: "edgex/{device}"}
+	p, err := newMQTTPublisher(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// the topic of the invalid device contains a wildcard, only its event fails
+	invalid := newTestEvent(`{"device":"invalid"}`)
+	invalid.Device = "device+"
+	errs := p.PublishBatch(newBatchItems(newTestEvent(`{"device":"1"}`), invalid, newTestEvent(`{"device":"2"}`)))
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Fatalf("expected only the event of the invalid device to fail, got %v", errs)
+	}
+
+	msg := receive(t, broker)
+	var decoded []map[string]string
+	if err = json.Unmarshal(msg.Payload, &decoded); err != nil || len(decoded) != 2 {
+		t.Errorf("expected a JSON array of 2 events, got %s, %v", msg.Payload, err)
+	}
+}
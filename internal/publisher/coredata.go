@@ -0,0 +1,41 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/types"
+)
+
+// coreDataPublisher posts the events to Core Data via REST, one by one as
+// Core Data has no endpoint adding several events at once.
+type coreDataPublisher struct{}
+
+func (p *coreDataPublisher) Publish(ctx context.Context, event *dsModels.Event) error {
+	responseBody, err := common.EventClient.AddBytes(event.EncodedEvent, ctx)
+	if err != nil {
+		var errClient types.ErrServiceClient
+		rejected := errors.As(err, &errClient) && errClient.StatusCode < http.StatusInternalServerError
+		err = fmt.Errorf("failed to push event to core data, response: %s, %v", responseBody, err)
+		if rejected {
+			// core data refused the event, there's no point in retrying it
+			return rejectedError{err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+func (p *coreDataPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,259 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+const (
+	queueFileExt = ".json"
+	tmpFileExt   = ".tmp"
+)
+
+// storedEvent is the on-disk representation of a queued event.
+type storedEvent struct {
+	Device      string
+	ContentType string
+	Correlation string
+	// Created is the time the event was queued in nanoseconds
+	Created int64
+	Payload []byte
+}
+
+// queueEntry is the in-memory index entry of a queued event.
+type queueEntry struct {
+	seq     uint64
+	device  string
+	size    int64
+	created int64
+}
+
+// diskQueue is a FIFO queue of the events which stores each event in its own
+// file, the file names are the zero-padded sequence numbers so the queue can
+// be restored in order after a restart.
+type diskQueue struct {
+	dir       string
+	maxEvents int
+	maxBytes  int64
+	maxAge    time.Duration
+
+	mutex   sync.Mutex
+	entries []queueEntry
+	devices map[string]int
+	bytes   int64
+	nextSeq uint64
+
+	droppedFull     uint64
+	droppedExpired  uint64
+	droppedRejected uint64
+}
+
+func newDiskQueue(dir string, maxEvents int, maxBytes int64, maxAge time.Duration) (*diskQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the queue directory %s: %v", dir, err)
+	}
+
+	q := &diskQueue{
+		dir:       dir,
+		maxEvents: maxEvents,
+		maxBytes:  maxBytes,
+		maxAge:    maxAge,
+		devices:   make(map[string]int),
+		nextSeq:   1,
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// load restores the index of the events stored by the previous run.
+func (q *diskQueue) load() error {
+	files, err := ioutil.ReadDir(q.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the queue directory %s: %v", q.dir, err)
+	}
+
+	for _, f := range files {
+		name := f.Name()
+		if strings.HasSuffix(name, tmpFileExt) {
+			// left over by an interrupted write
+			os.Remove(filepath.Join(q.dir, name))
+			continue
+		}
+		if f.IsDir() || !strings.HasSuffix(name, queueFileExt) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, queueFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		e, err := q.read(seq)
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("removing the corrupted queued event %s: %v", name, err))
+			os.Remove(q.path(seq))
+			continue
+		}
+		q.append(queueEntry{seq: seq, device: e.Device, size: int64(len(e.Payload)), created: e.Created})
+	}
+
+	sort.Slice(q.entries, func(i, j int) bool { return q.entries[i].seq < q.entries[j].seq })
+	if len(q.entries) > 0 {
+		q.nextSeq = q.entries[len(q.entries)-1].seq + 1
+		common.LoggingClient.Info(fmt.Sprintf("restored %d queued events from %s", len(q.entries), q.dir))
+	}
+	return nil
+}
+
+func (q *diskQueue) path(seq uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", seq, queueFileExt))
+}
+
+func (q *diskQueue) read(seq uint64) (storedEvent, error) {
+	var e storedEvent
+	data, err := ioutil.ReadFile(q.path(seq))
+	if err != nil {
+		return e, err
+	}
+	err = json.Unmarshal(data, &e)
+	return e, err
+}
+
+func (q *diskQueue) append(entry queueEntry) {
+	q.entries = append(q.entries, entry)
+	q.devices[entry.device]++
+	q.bytes += entry.size
+}
+
+// removeAt removes the i-th event from the queue, the caller must hold the mutex.
+func (q *diskQueue) removeAt(i int) {
+	entry := q.entries[i]
+	if err := os.Remove(q.path(entry.seq)); err != nil && !os.IsNotExist(err) {
+		common.LoggingClient.Error(fmt.Sprintf("failed to remove the queued event %d: %v", entry.seq, err))
+	}
+
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	q.bytes -= entry.size
+	if q.devices[entry.device]--; q.devices[entry.device] <= 0 {
+		delete(q.devices, entry.device)
+	}
+}
+
+// dropExpired removes the events exceeding MaxAge, the caller must hold the mutex.
+func (q *diskQueue) dropExpired() {
+	if q.maxAge <= 0 {
+		return
+	}
+	deadline := time.Now().Add(-q.maxAge).UnixNano()
+	for len(q.entries) > 0 && q.entries[0].created < deadline {
+		common.LoggingClient.Warn(fmt.Sprintf("dropping the queued event of device %s which exceeded MaxAge %v", q.entries[0].device, q.maxAge))
+		q.removeAt(0)
+		q.droppedExpired++
+	}
+}
+
+// push stores the event at the tail of the queue, then the oldest events are
+// dropped until the queue fits in its size limits.
+func (q *diskQueue) push(e storedEvent) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.dropExpired()
+
+	e.Created = time.Now().UnixNano()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	seq := q.nextSeq
+	tmp := q.path(seq) + tmpFileExt
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err = os.Rename(tmp, q.path(seq)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	q.nextSeq++
+	q.append(queueEntry{seq: seq, device: e.Device, size: int64(len(e.Payload)), created: e.Created})
+
+	for len(q.entries) > 0 &&
+		((q.maxEvents > 0 && len(q.entries) > q.maxEvents) || (q.maxBytes > 0 && q.bytes > q.maxBytes)) {
+		common.LoggingClient.Warn(fmt.Sprintf("the event queue is full, dropping the oldest event of device %s", q.entries[0].device))
+		q.removeAt(0)
+		q.droppedFull++
+	}
+	return nil
+}
+
+// peek returns the oldest event which hasn't exceeded MaxAge.
+func (q *diskQueue) peek() (uint64, storedEvent, bool, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.dropExpired()
+	if len(q.entries) == 0 {
+		return 0, storedEvent{}, false, nil
+	}
+
+	seq := q.entries[0].seq
+	e, err := q.read(seq)
+	return seq, e, true, err
+}
+
+// remove removes the event with the given sequence number, rejected specifies
+// whether the event is dropped because it was rejected by its destination.
+func (q *diskQueue) remove(seq uint64, rejected bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i := range q.entries {
+		if q.entries[i].seq == seq {
+			q.removeAt(i)
+			if rejected {
+				q.droppedRejected++
+			}
+			return
+		}
+	}
+}
+
+// hasDevice returns whether there are queued events of the device.
+func (q *diskQueue) hasDevice(deviceName string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.devices[deviceName] > 0
+}
+
+func (q *diskQueue) metrics() common.EventQueueMetrics {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return common.EventQueueMetrics{
+		Depth:           uint64(len(q.entries)),
+		Bytes:           uint64(q.bytes),
+		DroppedFull:     q.droppedFull,
+		DroppedExpired:  q.droppedExpired,
+		DroppedRejected: q.droppedRejected,
+	}
+}
@@ -0,0 +1,250 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Keys of the [EventPublisher.Optional] settings used by the MQTT publisher.
+const (
+	OptClientId       = "ClientId"
+	OptUsername       = "Username"
+	OptPassword       = "Password"
+	OptQos            = "Qos"
+	OptRetained       = "Retained"
+	OptKeepAlive      = "KeepAlive"
+	OptTimeout        = "Timeout"
+	OptCaFile         = "CaFile"
+	OptCertFile       = "CertFile"
+	OptKeyFile        = "KeyFile"
+	OptSkipCertVerify = "SkipCertVerify"
+
+	defaultKeepAlive = 60 * time.Second
+	defaultTimeout   = 5 * time.Second
+	// disconnectQuiesce is the time in milliseconds given to the pending work
+	// when the publisher is closed
+	disconnectQuiesce = 250
+)
+
+// mqttPublisher publishes the events to a MQTT broker, the payload is the
+// event encoded in JSON, or in CBOR if it contains binary readings.
+type mqttPublisher struct {
+	client   mqtt.Client
+	topic    string
+	qos      byte
+	retained bool
+	timeout  time.Duration
+}
+
+func newMQTTPublisher(config common.EventPublisherInfo) (*mqttPublisher, error) {
+	if config.Topic == "" {
+		return nil, fmt.Errorf("the Topic of the MQTT EventPublisher is not specified")
+	}
+
+	protocol := strings.ToLower(config.Protocol)
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	opts := mqtt.NewClientOptions()
+	opts.AddBroker(fmt.Sprintf("%s://%s:%d", protocol, config.Host, config.Port))
+	opts.SetClientID(common.ServiceName)
+	if v, ok := config.Optional[OptClientId]; ok && v != "" {
+		opts.SetClientID(v)
+	}
+	opts.SetUsername(config.Optional[OptUsername])
+	opts.SetPassword(config.Optional[OptPassword])
+	// the client reconnects by itself once it has been connected
+	opts.SetAutoReconnect(true)
+
+	keepAlive, err := parseDuration(config.Optional, OptKeepAlive, defaultKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+	opts.SetKeepAlive(keepAlive)
+	timeout, err := parseDuration(config.Optional, OptTimeout, defaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	opts.SetConnectTimeout(timeout)
+
+	tlsConfig, err := newTLSConfig(config.Optional)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	p := &mqttPublisher{topic: config.Topic, timeout: timeout}
+	if v, ok := config.Optional[OptQos]; ok && v != "" {
+		qos, err := strconv.ParseUint(v, 10, 8)
+		if err != nil || qos > 2 {
+			return nil, fmt.Errorf("the %s of the MQTT EventPublisher should be 0, 1 or 2, got %s", OptQos, v)
+		}
+		p.qos = byte(qos)
+	}
+	if v, ok := config.Optional[OptRetained]; ok && v != "" {
+		if p.retained, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("the %s of the MQTT EventPublisher cannot be parsed, %v", OptRetained, err)
+		}
+	}
+
+	p.client = mqtt.NewClient(opts)
+	if err = p.connect(); err != nil {
+		// the connection is retried when the next event is published
+		common.LoggingClient.Warn(fmt.Sprintf("failed to connect to the MQTT broker %s:%d, %v", config.Host, config.Port, err))
+	}
+	return p, nil
+}
+
+func parseDuration(optional map[string]string, key string, defaultValue time.Duration) (time.Duration, error) {
+	v, ok := optional[key]
+	if !ok || v == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("the %s of the MQTT EventPublisher cannot be parsed, %v", key, err)
+	}
+	return d, nil
+}
+
+// newTLSConfig returns the TLS configuration of the CaFile, CertFile, KeyFile
+// and SkipCertVerify settings, or nil if none of them is set.
+func newTLSConfig(optional map[string]string) (*tls.Config, error) {
+	caFile, certFile, keyFile := optional[OptCaFile], optional[OptCertFile], optional[OptKeyFile]
+	skipVerify := optional[OptSkipCertVerify]
+	if caFile == "" && certFile == "" && keyFile == "" && skipVerify == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{}
+	if skipVerify != "" {
+		var err error
+		if config.InsecureSkipVerify, err = strconv.ParseBool(skipVerify); err != nil {
+			return nil, fmt.Errorf("the %s of the MQTT EventPublisher cannot be parsed, %v", OptSkipCertVerify, err)
+		}
+	}
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s of the MQTT EventPublisher, %v", OptCaFile, err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("the %s %s of the MQTT EventPublisher contains no PEM certificate", OptCaFile, caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("both the %s and %s of the MQTT EventPublisher should be specified", OptCertFile, OptKeyFile)
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate of the MQTT EventPublisher, %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func (p *mqttPublisher) connect() error {
+	token := p.client.Connect()
+	if !token.WaitTimeout(p.timeout) {
+		return fmt.Errorf("timed out after %v", p.timeout)
+	}
+	return token.Error()
+}
+
+func (p *mqttPublisher) Publish(ctx context.Context, event *dsModels.Event) error {
+	topic, err := buildTopic(p.topic, event.Device)
+	if err != nil {
+		return err
+	}
+	return p.publish(ctx, topic, event.EncodedEvent)
+}
+
+func (p *mqttPublisher) publish(ctx context.Context, topic string, payload []byte) error {
+	if strings.ContainsAny(topic, "+#") {
+		return fmt.Errorf("topic %s contains MQTT wildcard characters", topic)
+	}
+
+	if !p.client.IsConnected() {
+		// the client was never connected, or failed to reconnect
+		if err := p.connect(); err != nil {
+			return fmt.Errorf("failed to connect to the MQTT broker, %v", err)
+		}
+	} else if !p.client.IsConnectionOpen() {
+		// the client drops the QoS 0 messages published while it's reconnecting
+		return fmt.Errorf("the connection to the MQTT broker is lost, reconnecting")
+	}
+
+	timeout := p.timeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	token := p.client.Publish(topic, p.qos, p.retained, payload)
+	if !token.WaitTimeout(timeout) {
+		return fmt.Errorf("timed out publishing to the MQTT topic %s after %v", topic, timeout)
+	}
+	return token.Error()
+}
+
+// PublishBatch publishes the JSON encoded events, the events with the same topic
+// are sent in one message whose payload is the JSON array of the events. The
+// events of a message which failed to be published get its error. A MQTT
+// message carries no correlation ID or content type, the context of the first
+// event of a message only bounds the time it's published in.
+func (p *mqttPublisher) PublishBatch(batch []batchItem) []error {
+	errs := make([]error, len(batch))
+	var topics []string
+	indexes := make(map[string][]int)
+	for i, item := range batch {
+		topic, err := buildTopic(p.topic, item.event.Device)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if _, ok := indexes[topic]; !ok {
+			topics = append(topics, topic)
+		}
+		indexes[topic] = append(indexes[topic], i)
+	}
+
+	for _, topic := range topics {
+		payloads := make([][]byte, len(indexes[topic]))
+		for j, i := range indexes[topic] {
+			payloads[j] = batch[i].event.EncodedEvent
+		}
+		payload := append([]byte{'['}, bytes.Join(payloads, []byte{','})...)
+		payload = append(payload, ']')
+		if err := p.publish(batch[indexes[topic][0]].ctx, topic, payload); err != nil {
+			for _, i := range indexes[topic] {
+				errs[i] = err
+			}
+		}
+	}
+	return errs
+}
+
+func (p *mqttPublisher) Close() error {
+	p.client.Disconnect(disconnectQuiesce)
+	return nil
+}
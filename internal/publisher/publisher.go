@@ -0,0 +1,92 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package publisher contains the implementations of common.EventPublisher.
+package publisher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+)
+
+const (
+	TypeCoreData = "coredata"
+	TypeMQTT     = "mqtt"
+
+	DevicePlaceholder  = "{device}"
+	ProfilePlaceholder = "{profile}"
+)
+
+// NewEventPublisher creates the EventPublisher of the Type given in the
+// [EventPublisher] configuration, Core Data is used if no Type is specified.
+// The EventPublisher is wrapped by the batching and the store-and-forward queue
+// if they're enabled.
+func NewEventPublisher(config common.EventPublisherInfo) (common.EventPublisher, error) {
+	var p common.EventPublisher
+	switch strings.ToLower(config.Type) {
+	case "", TypeCoreData:
+		if config.Batch.Enabled {
+			// the batches would still be posted event by event, only later
+			return nil, fmt.Errorf("the Core Data EventPublisher doesn't support batches, disable the [EventPublisher.Batch] settings")
+		}
+		p = &coreDataPublisher{}
+	case TypeMQTT:
+		mqttPublisher, err := newMQTTPublisher(config)
+		if err != nil {
+			return nil, err
+		}
+		p = mqttPublisher
+	default:
+		return nil, fmt.Errorf("unsupported EventPublisher type %s", config.Type)
+	}
+
+	if config.Batch.Enabled {
+		bp, err := newBatchPublisher(p, config.Batch)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p = bp
+	}
+
+	if !config.StoreAndForward.Enabled {
+		return p, nil
+	}
+	sf, err := newStoreForwardPublisher(p, config.StoreAndForward)
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	return sf, nil
+}
+
+// QueueMetrics returns the metrics of the store-and-forward queue of the
+// current EventPublisher, they are all zero if the queue is disabled.
+func QueueMetrics() common.EventQueueMetrics {
+	if sf, ok := common.Publisher.(*storeForwardPublisher); ok {
+		return sf.queue.metrics()
+	}
+	return common.EventQueueMetrics{}
+}
+
+// buildTopic replaces the placeholders of the topic template with the names
+// of the Device and its DeviceProfile.
+func buildTopic(template string, deviceName string) (string, error) {
+	profileName := ""
+	if strings.Contains(template, ProfilePlaceholder) {
+		device, ok := cache.Devices().ForName(deviceName)
+		if !ok {
+			return "", fmt.Errorf("device %s cannot be found in cache", deviceName)
+		}
+		profileName = device.Profile.Name
+	}
+
+	r := strings.NewReplacer(DevicePlaceholder, deviceName, ProfilePlaceholder, profileName)
+	return r.Replace(template), nil
+}
@@ -0,0 +1,233 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+const testDeviceName = "Random-Boolean-Generator01"
+
+func init() {
+	common.ServiceName = "device-sdk-test"
+	common.LoggingClient = logger.NewMockClient()
+	common.ValueDescriptorClient = &mock.ValueDescriptorMock{}
+	common.DeviceClient = &mock.DeviceClientMock{}
+	common.ProvisionWatcherClient = &mock.ProvisionWatcherClientMock{}
+	cache.InitCache()
+}
+
+func newTestEvent(payload string) *dsModels.Event {
+	return &dsModels.Event{
+		Event:        contract.Event{Device: testDeviceName},
+		EncodedEvent: []byte(payload),
+	}
+}
+
+func receive(t *testing.T, broker *mock.MqttBrokerMock) *packets.PublishPacket {
+	select {
+	case p := <-broker.Messages:
+		return p
+	case <-time.After(time.Second):
+		t.Fatal("no message was received by the broker")
+		return nil
+	}
+}
+
+func TestNewEventPublisher(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      common.EventPublisherInfo
+		expectedErr bool
+	}{
+		{"default", common.EventPublisherInfo{}, false},
+		{"coredata", common.EventPublisherInfo{Type: "CoreData"}, false},
+		{"coredata batch", common.EventPublisherInfo{Type: "CoreData", Batch: common.BatchInfo{Enabled: true}}, true},
+		{"unknown type", common.EventPublisherInfo{Type: "zeromq"}, true},
+		{"mqtt without topic", common.EventPublisherInfo{Type: TypeMQTT}, true},
+		{"mqtt invalid qos", common.EventPublisherInfo{Type: TypeMQTT, Topic: "events", Optional: map[string]string{OptQos: "3"}}, true},
+		{"mqtt invalid retained", common.EventPublisherInfo{Type: TypeMQTT, Topic: "events", Optional: map[string]string{OptRetained: "maybe"}}, true},
+		{"mqtt invalid keepalive", common.EventPublisherInfo{Type: TypeMQTT, Topic: "events", Optional: map[string]string{OptKeepAlive: "60"}}, true},
+		{"mqtt invalid skip cert verify", common.EventPublisherInfo{Type: TypeMQTT, Topic: "events", Optional: map[string]string{OptSkipCertVerify: "maybe"}}, true},
+		{"mqtt missing ca file", common.EventPublisherInfo{Type: TypeMQTT, Topic: "events", Optional: map[string]string{OptCaFile: "./missing.pem"}}, true},
+		{"mqtt cert without key", common.EventPublisherInfo{Type: TypeMQTT, Topic: "events", Optional: map[string]string{OptCertFile: "./cert.pem"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewEventPublisher(tt.config)
+			if tt.expectedErr && err == nil {
+				t.Errorf("expected an error for %v", tt.config)
+			} else if !tt.expectedErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if p != nil {
+				p.Close()
+			}
+		})
+	}
+}
+
+func TestBuildTopic(t *testing.T) {
+	device, ok := cache.Devices().ForName(testDeviceName)
+	if !ok {
+		t.Fatalf("device %s is not found in cache", testDeviceName)
+	}
+
+	topic, err := buildTopic("edgex/events/{profile}/{device}", testDeviceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "edgex/events/" + device.Profile.Name + "/" + testDeviceName
+	if topic != expected {
+		t.Errorf("expected topic %s, got %s", expected, topic)
+	}
+
+	if _, err = buildTopic("edgex/events/{profile}", "Unknown-Device"); err == nil {
+		t.Error("expected an error for the device which is not in cache")
+	}
+	if topic, err = buildTopic("edgex/{device}", "Unknown-Device"); err != nil || topic != "edgex/Unknown-Device" {
+		t.Errorf("unexpected topic %s, error %v", topic, err)
+	}
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	broker, err := mock.NewMqttBrokerMock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer broker.Close()
+
+	config := common.EventPublisherInfo{
+		Type:     TypeMQTT,
+		Host:     broker.Host(),
+		Port:     broker.Port(),
+		Protocol: "tcp",
+		Topic:    "edgex/{device}",
+		Optional: map[string]string{OptQos: "1", OptUsername: "user", OptPassword: "secret"},
+	}
+	p, err := NewEventPublisher(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	c := <-broker.Connections
+	if c.ClientIdentifier != common.ServiceName || c.Username != "user" || string(c.Password) != "secret" {
+		t.Errorf("unexpected CONNECT packet %+v", c)
+	}
+
+	if err = p.Publish(context.Background(), newTestEvent("first")); err != nil {
+		t.Fatal(err)
+	}
+	msg := receive(t, broker)
+	if msg.TopicName != "edgex/"+testDeviceName || msg.Qos != 1 || !bytes.Equal(msg.Payload, []byte("first")) {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	// the publisher reconnects when the connection to the broker is lost
+	broker.DropConnections()
+	published := false
+	for i := 0; i < 100 && !published; i++ {
+		published = p.Publish(context.Background(), newTestEvent("second")) == nil
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !published {
+		t.Fatal("the event is not published after the connection was lost")
+	}
+	msg = receive(t, broker)
+	if !bytes.Equal(msg.Payload, []byte("second")) {
+		t.Errorf("unexpected payload %s", msg.Payload)
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	config, err := newTLSConfig(map[string]string{OptQos: "1"})
+	if err != nil || config != nil {
+		t.Errorf("expected no TLS configuration without the TLS settings, got %v, %v", config, err)
+	}
+
+	config, err = newTLSConfig(map[string]string{OptSkipCertVerify: "true"})
+	if err != nil || config == nil || !config.InsecureSkipVerify {
+		t.Errorf("expected a TLS configuration skipping the verification, got %v, %v", config, err)
+	}
+
+	dir, err := ioutil.TempDir("", "mqtt-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeTestCertificate(t, dir)
+
+	config, err = newTLSConfig(map[string]string{OptCaFile: certFile, OptCertFile: certFile, OptKeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.RootCAs == nil || len(config.Certificates) != 1 || config.InsecureSkipVerify {
+		t.Errorf("unexpected TLS configuration %+v", config)
+	}
+
+	// the key isn't a certificate
+	if _, err = newTLSConfig(map[string]string{OptCaFile: keyFile}); err == nil {
+		t.Error("expected an error for the CaFile without certificate")
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate and its key to the dir
+// and returns their paths.
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
@@ -0,0 +1,265 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+const (
+	defaultRetryInterval    = time.Second
+	defaultMaxRetryInterval = 5 * time.Minute
+)
+
+// rejectedError indicates the event was rejected by its destination, so
+// publishing it again wouldn't succeed either.
+type rejectedError struct {
+	err error
+}
+
+func (e rejectedError) Error() string {
+	return e.err.Error()
+}
+
+func isRejected(err error) bool {
+	var r rejectedError
+	return errors.As(err, &r)
+}
+
+// storeForwardPublisher wraps an EventPublisher, the events which fail to be
+// published are stored in a diskQueue and retried with exponential backoff.
+// Once a Device has queued events its new events are queued as well, so the
+// events of each Device are delivered in order.
+type storeForwardPublisher struct {
+	next common.EventPublisher
+	// replay is the EventPublisher the queued events are forwarded to, the
+	// one wrapped by the batching if any, so the queued events, forwarded one
+	// at a time, don't wait for a batch each
+	replay           common.EventPublisher
+	queue            *diskQueue
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+
+	// locks holds the lock of the devices whose events are being published,
+	// an entry is removed once no event of its device is
+	locksMutex sync.Mutex
+	locks      map[string]*deviceLock
+
+	wakeCh chan struct{}
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newStoreForwardPublisher(next common.EventPublisher, config common.StoreAndForwardInfo) (*storeForwardPublisher, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("the Dir of the StoreAndForward queue is not specified")
+	}
+	maxAge, err := parseConfigDuration("MaxAge", config.MaxAge, 0)
+	if err != nil {
+		return nil, err
+	}
+	retryInterval, err := parseConfigDuration("RetryInterval", config.RetryInterval, defaultRetryInterval)
+	if err != nil {
+		return nil, err
+	}
+	maxRetryInterval, err := parseConfigDuration("MaxRetryInterval", config.MaxRetryInterval, defaultMaxRetryInterval)
+	if err != nil {
+		return nil, err
+	}
+	if retryInterval <= 0 || maxRetryInterval < retryInterval {
+		return nil, fmt.Errorf("the RetryInterval %v should be greater than zero and not exceed MaxRetryInterval %v", retryInterval, maxRetryInterval)
+	}
+
+	queue, err := newDiskQueue(config.Dir, config.MaxEvents, config.MaxBytes, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &storeForwardPublisher{
+		next:             next,
+		replay:           next,
+		queue:            queue,
+		retryInterval:    retryInterval,
+		maxRetryInterval: maxRetryInterval,
+		locks:            make(map[string]*deviceLock),
+		wakeCh:           make(chan struct{}, 1),
+		stopCh:           make(chan struct{}),
+	}
+	if bp, ok := next.(*batchPublisher); ok {
+		p.replay = bp.next
+	}
+	p.wg.Add(1)
+	go p.forward()
+	// forward the events restored from the disk
+	p.wake()
+	return p, nil
+}
+
+func parseConfigDuration(key string, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("the %s %s of the StoreAndForward queue cannot be parsed, %v", key, value, err)
+	}
+	return d, nil
+}
+
+// deviceLock serializes the events of a device, refs is the number of events
+// holding or waiting for it.
+type deviceLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lockDevice locks the events of the device until unlockDevice is called.
+func (p *storeForwardPublisher) lockDevice(deviceName string) {
+	p.locksMutex.Lock()
+	lock, ok := p.locks[deviceName]
+	if !ok {
+		lock = &deviceLock{}
+		p.locks[deviceName] = lock
+	}
+	lock.refs++
+	p.locksMutex.Unlock()
+
+	lock.Lock()
+}
+
+// unlockDevice unlocks the events of the device, the lock is removed once no
+// event of the device holds or waits for it.
+func (p *storeForwardPublisher) unlockDevice(deviceName string) {
+	p.locksMutex.Lock()
+	defer p.locksMutex.Unlock()
+
+	lock := p.locks[deviceName]
+	lock.Unlock()
+	if lock.refs--; lock.refs == 0 {
+		delete(p.locks, deviceName)
+	}
+}
+
+func (p *storeForwardPublisher) wake() {
+	select {
+	case p.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Publish publishes the event directly if the Device has no queued events,
+// otherwise or if publishing fails the event is queued and ErrEventQueued is returned.
+func (p *storeForwardPublisher) Publish(ctx context.Context, event *dsModels.Event) error {
+	p.lockDevice(event.Device)
+	defer p.unlockDevice(event.Device)
+
+	if !p.queue.hasDevice(event.Device) {
+		err := p.next.Publish(ctx, event)
+		if err == nil || isRejected(err) {
+			return err
+		}
+		common.LoggingClient.Warn(fmt.Sprintf("failed to publish the event of device %s, queuing it: %v", event.Device, err))
+	}
+
+	err := p.queue.push(storedEvent{
+		Device:      event.Device,
+		ContentType: clients.FromContext(clients.ContentType, ctx),
+		Correlation: clients.FromContext(clients.CorrelationHeader, ctx),
+		Payload:     event.EncodedEvent,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue the event of device %s: %v", event.Device, err)
+	}
+	p.wake()
+	return common.ErrEventQueued
+}
+
+// forward publishes the queued events in order, it waits with exponential
+// backoff after a failure.
+func (p *storeForwardPublisher) forward() {
+	defer p.wg.Done()
+
+	backoff := p.retryInterval
+	for {
+		if p.flush() {
+			backoff = p.retryInterval
+			select {
+			case <-p.stopCh:
+				return
+			case <-p.wakeCh:
+			}
+			continue
+		}
+
+		common.LoggingClient.Debug(fmt.Sprintf("retrying the queued events in %v", backoff))
+		timer := time.NewTimer(backoff)
+		select {
+		case <-p.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > p.maxRetryInterval {
+			backoff = p.maxRetryInterval
+		}
+	}
+}
+
+// flush publishes the queued events until the queue is empty, it returns
+// false if an event failed to be published.
+func (p *storeForwardPublisher) flush() bool {
+	for {
+		select {
+		case <-p.stopCh:
+			return false
+		default:
+		}
+
+		seq, e, ok, err := p.queue.peek()
+		if !ok {
+			return true
+		}
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("dropping the queued event %d which cannot be read: %v", seq, err))
+			p.queue.remove(seq, false)
+			continue
+		}
+
+		ctx := context.WithValue(context.Background(), common.CorrelationHeader, e.Correlation)
+		ctx = context.WithValue(ctx, clients.ContentType, e.ContentType)
+		event := &dsModels.Event{Event: contract.Event{Device: e.Device}, EncodedEvent: e.Payload}
+		err = p.replay.Publish(ctx, event)
+		if err != nil && !isRejected(err) {
+			common.LoggingClient.Warn(fmt.Sprintf("failed to publish the queued event of device %s: %v", e.Device, err))
+			return false
+		}
+
+		if err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("dropping the queued event of device %s: %v", e.Device, err))
+		} else {
+			common.LoggingClient.Debug(fmt.Sprintf("published the queued event of device %s", e.Device), clients.CorrelationHeader, e.Correlation)
+		}
+		p.queue.remove(seq, err != nil)
+	}
+}
+
+// Close stops forwarding the queued events, they are kept on the disk and
+// forwarded after the next start.
+func (p *storeForwardPublisher) Close() error {
+	close(p.stopCh)
+	p.wg.Wait()
+	return p.next.Close()
+}
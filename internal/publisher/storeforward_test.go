@@ -0,0 +1,271 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+// publisherMock records the payloads of the published events, it fails while down is set.
+type publisherMock struct {
+	mutex     sync.Mutex
+	down      bool
+	reject    bool
+	published []string
+	types     []string
+}
+
+func (p *publisherMock) Publish(ctx context.Context, event *dsModels.Event) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.reject {
+		return rejectedError{err: fmt.Errorf("400 - invalid event")}
+	}
+	if p.down {
+		return fmt.Errorf("connection refused")
+	}
+	p.published = append(p.published, event.Device+":"+string(event.EncodedEvent))
+	p.types = append(p.types, clients.FromContext(clients.ContentType, ctx))
+	return nil
+}
+
+func (p *publisherMock) Close() error {
+	return nil
+}
+
+func (p *publisherMock) setDown(down bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.down = down
+}
+
+func (p *publisherMock) result() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string(nil), p.published...)
+}
+
+func newTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "event-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func publishEvent(p common.EventPublisher, device string, payload string, contentType string) error {
+	ctx := context.WithValue(context.Background(), clients.ContentType, contentType)
+	return p.Publish(ctx, &dsModels.Event{Event: contract.Event{Device: device}, EncodedEvent: []byte(payload)})
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for i := 0; i < 200; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the condition")
+}
+
+func TestStoreAndForward(t *testing.T) {
+	dir := newTempDir(t)
+	defer os.RemoveAll(dir)
+
+	next := &publisherMock{down: true}
+	config := common.StoreAndForwardInfo{Enabled: true, Dir: dir, RetryInterval: "10ms", MaxRetryInterval: "20ms"}
+	p, err := newStoreForwardPublisher(next, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if err = publishEvent(p, "device1", "1", clients.ContentTypeJSON); err != common.ErrEventQueued {
+		t.Fatalf("expected ErrEventQueued, got %v", err)
+	}
+	next.setDown(false)
+	// device1 has a queued event, so the new event is queued to keep the order
+	if err = publishEvent(p, "device1", "2", clients.ContentTypeCBOR); err != common.ErrEventQueued {
+		t.Fatalf("expected ErrEventQueued, got %v", err)
+	}
+
+	waitFor(t, func() bool { return p.queue.metrics().Depth == 0 })
+	if err = publishEvent(p, "device1", "3", clients.ContentTypeJSON); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []string{"device1:1", "device1:2", "device1:3"}
+	if fmt.Sprint(next.result()) != fmt.Sprint(expected) {
+		t.Errorf("expected events %v, got %v", expected, next.result())
+	}
+	if next.types[1] != clients.ContentTypeCBOR {
+		t.Errorf("the content type of the queued event is not kept, got %s", next.types[1])
+	}
+}
+
+func TestStoreAndForwardReplayBypassesBatching(t *testing.T) {
+	dir := newTempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := newDiskQueue(dir, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		q.push(storedEvent{Device: "device1", ContentType: clients.ContentTypeJSON, Payload: []byte(fmt.Sprintf("%d", i))})
+	}
+
+	next := &publisherMock{}
+	bp, err := newBatchPublisher(next, common.BatchInfo{MaxBatchSize: 100, MaxBatchWait: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := newStoreForwardPublisher(bp, common.StoreAndForwardInfo{Enabled: true, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// the restored events would wait for MaxBatchWait each if they were batched
+	waitFor(t, func() bool { return p.queue.metrics().Depth == 0 })
+	expected := []string{"device1:1", "device1:2", "device1:3", "device1:4", "device1:5"}
+	if fmt.Sprint(next.result()) != fmt.Sprint(expected) {
+		t.Errorf("expected events %v, got %v", expected, next.result())
+	}
+}
+
+func TestStoreAndForwardRejected(t *testing.T) {
+	dir := newTempDir(t)
+	defer os.RemoveAll(dir)
+
+	next := &publisherMock{reject: true}
+	p, err := newStoreForwardPublisher(next, common.StoreAndForwardInfo{Enabled: true, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	err = publishEvent(p, "device1", "1", clients.ContentTypeJSON)
+	if err == nil || err == common.ErrEventQueued {
+		t.Errorf("the rejected event is not supposed to be queued, got %v", err)
+	}
+	if depth := p.queue.metrics().Depth; depth != 0 {
+		t.Errorf("expected an empty queue, got depth %d", depth)
+	}
+}
+
+func TestStoreAndForwardDeviceLocks(t *testing.T) {
+	dir := newTempDir(t)
+	defer os.RemoveAll(dir)
+
+	next := &publisherMock{}
+	p, err := newStoreForwardPublisher(next, common.StoreAndForwardInfo{Enabled: true, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := publishEvent(p, fmt.Sprintf("device%d", i%10), "payload", clients.ContentTypeJSON); err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(next.result()) != 50 {
+		t.Errorf("expected 50 events, got %d", len(next.result()))
+	}
+	// the lock of a device is removed once none of its events is published
+	p.locksMutex.Lock()
+	defer p.locksMutex.Unlock()
+	if len(p.locks) != 0 {
+		t.Errorf("expected no device lock, got %d", len(p.locks))
+	}
+}
+
+func TestDiskQueueLimits(t *testing.T) {
+	dir := newTempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := newDiskQueue(dir, 2, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err = q.push(storedEvent{Device: "device1", Payload: []byte(fmt.Sprintf("%d", i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := q.metrics()
+	if m.Depth != 2 || m.DroppedFull != 1 || m.Bytes != 2 {
+		t.Errorf("unexpected metrics %+v after exceeding MaxEvents", m)
+	}
+
+	if err = q.push(storedEvent{Device: "device1", Payload: []byte("0123456789")}); err != nil {
+		t.Fatal(err)
+	}
+	m = q.metrics()
+	if m.Depth != 1 || m.DroppedFull != 3 || m.Bytes != 10 {
+		t.Errorf("unexpected metrics %+v after exceeding MaxBytes", m)
+	}
+
+	q.maxAge = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if _, _, ok, _ := q.peek(); ok {
+		t.Error("the expired event is supposed to be dropped")
+	}
+	if m = q.metrics(); m.Depth != 0 || m.DroppedExpired != 1 || q.hasDevice("device1") {
+		t.Errorf("unexpected metrics %+v after exceeding MaxAge", m)
+	}
+}
+
+func TestDiskQueueRestore(t *testing.T) {
+	dir := newTempDir(t)
+	defer os.RemoveAll(dir)
+
+	q, err := newDiskQueue(dir, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.push(storedEvent{Device: "device1", Payload: []byte("1")})
+	q.push(storedEvent{Device: "device2", Payload: []byte("2")})
+	ioutil.WriteFile(dir+"/00000000000000000003.json.tmp", []byte("{"), 0600)
+
+	restored, err := newDiskQueue(dir, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := restored.metrics(); m.Depth != 2 {
+		t.Fatalf("expected 2 restored events, got %d", m.Depth)
+	}
+	seq, e, ok, err := restored.peek()
+	if !ok || err != nil || e.Device != "device1" || string(e.Payload) != "1" {
+		t.Errorf("unexpected head of the restored queue %+v, %v", e, err)
+	}
+	restored.remove(seq, false)
+	restored.push(storedEvent{Device: "device3", Payload: []byte("3")})
+	if _, e, _, _ = restored.peek(); e.Device != "device2" {
+		t.Errorf("expected the event of device2 at the head, got %s", e.Device)
+	}
+}
@@ -0,0 +1,288 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/google/uuid"
+)
+
+// Policies of the readings which fail the assertion of their DeviceResource.
+const (
+	AssertionPolicyDisable = "disable"
+	AssertionPolicyWarn    = "warn"
+	AssertionPolicyDrop    = "drop"
+)
+
+// AssertionPolicyAttribute is the DeviceResource attribute which overrides
+// the [Device] AssertionPolicy for the DeviceResource.
+const AssertionPolicyAttribute = "assertionPolicy"
+
+// assertionOperators are the comparison operators of the assertions, the two
+// characters operators come first so that ">=" isn't parsed as ">".
+var assertionOperators = []string{">=", "<=", "!=", "==", ">", "<"}
+
+// assertionRange matches the range assertions such as [0,100] or (0,], an
+// empty bound is unbounded.
+var assertionRange = regexp.MustCompile(`^([\[(])([^,]*),([^,]*)([\])])$`)
+
+var (
+	recoveryMutex sync.Mutex
+	// recovering maps the name of the devices disabled by a failed assertion
+	// to the DeviceResources whose assertion failed, which are mapped to their
+	// number of consecutive passing assertions since.
+	recovering = make(map[string]map[string]int)
+)
+
+// AssertionPolicy returns the policy of the readings of the DeviceResource
+// which fail its assertion, "disable" unless the assertionPolicy attribute of
+// the DeviceResource or the [Device] AssertionPolicy says otherwise.
+func AssertionPolicy(dr *contract.DeviceResource) string {
+	policy := common.CurrentConfig.Device.AssertionPolicy
+	if p, ok := dr.Attributes[AssertionPolicyAttribute]; ok {
+		policy = p
+	}
+
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case AssertionPolicyWarn:
+		return AssertionPolicyWarn
+	case AssertionPolicyDrop:
+		return AssertionPolicyDrop
+	default:
+		return AssertionPolicyDisable
+	}
+}
+
+// CheckAssertion returns an error if cv fails the assertion of the
+// DeviceResource, along with the policy the caller should apply to the reading.
+// The device is marked DISABLED when the policy is "disable", and enabled again
+// once every DeviceResource whose assertion failed has passed [Device]
+// AssertionRecoveryReads consecutive assertions.
+func CheckAssertion(cv *dsModels.CommandValue, dr *contract.DeviceResource, device *contract.Device) (string, error) {
+	assertion := dr.Properties.Value.Assertion
+	if assertion == "" {
+		return "", nil
+	}
+
+	policy := AssertionPolicy(dr)
+	err := EvaluateAssertion(cv, assertion)
+	if err == nil {
+		recordAssertionPass(device, dr.Name)
+		return policy, nil
+	}
+
+	switch policy {
+	case AssertionPolicyWarn:
+		common.LoggingClient.Warn(err.Error())
+	case AssertionPolicyDrop:
+		common.LoggingClient.Warn(fmt.Sprintf("%v, the reading is dropped", err))
+	default:
+		common.LoggingClient.Error(err.Error())
+		disableForAssertion(device, dr.Name)
+	}
+	return policy, err
+}
+
+// AssertionRecovering returns whether the device has been disabled by a
+// failed assertion and is waiting for passing assertions to be enabled again,
+// such a device can still be read.
+func AssertionRecovering(deviceName string) bool {
+	recoveryMutex.Lock()
+	defer recoveryMutex.Unlock()
+	_, ok := recovering[deviceName]
+	return ok
+}
+
+// EvaluateAssertion returns an error if cv fails the assertion. The numeric
+// values are compared numerically with a plain number, an operator followed by
+// a number such as ">=10", or a range such as "[0,100]". The other values are
+// compared as strings with the assertion, optionally prefixed by "==" or "!=".
+func EvaluateAssertion(cv *dsModels.CommandValue, assertion string) error {
+	a := strings.TrimSpace(assertion)
+	failed := fmt.Errorf("assertion (%s) failed with value: %s", assertion, cv.ValueToString())
+
+	v, numeric, err := NumericValue(cv)
+	if err != nil {
+		return fmt.Errorf("assertion (%s) failed: %v", assertion, err)
+	}
+	if !numeric {
+		switch {
+		case strings.HasPrefix(a, "=="):
+			a = strings.TrimSpace(a[2:])
+		case strings.HasPrefix(a, "!="):
+			if cv.ValueToString() == strings.TrimSpace(a[2:]) {
+				return failed
+			}
+			return nil
+		default:
+			a = assertion
+		}
+		if cv.ValueToString() != a {
+			return failed
+		}
+		return nil
+	}
+
+	if m := assertionRange.FindStringSubmatch(a); m != nil {
+		ok, err := inRange(v, m[1] == "[", m[2], m[3], m[4] == "]")
+		if err != nil {
+			return fmt.Errorf("invalid assertion (%s): %v", assertion, err)
+		}
+		if !ok {
+			return failed
+		}
+		return nil
+	}
+
+	op := "=="
+	for _, o := range assertionOperators {
+		if strings.HasPrefix(a, o) {
+			op = o
+			a = a[len(o):]
+			break
+		}
+	}
+	operand, err := parseAssertionNumber(a)
+	if err != nil {
+		if op == "==" && a == strings.TrimSpace(assertion) {
+			// not a number, fall back to the comparison of the strings
+			if cv.ValueToString() != assertion {
+				return failed
+			}
+			return nil
+		}
+		return fmt.Errorf("invalid assertion (%s): %v", assertion, err)
+	}
+
+	if !compare(v.Cmp(operand), op) {
+		return failed
+	}
+	return nil
+}
+
+func inRange(v *big.Float, minInclusive bool, min string, max string, maxInclusive bool) (bool, error) {
+	if strings.TrimSpace(min) != "" {
+		bound, err := parseAssertionNumber(min)
+		if err != nil {
+			return false, err
+		}
+		if c := v.Cmp(bound); c < 0 || (c == 0 && !minInclusive) {
+			return false, nil
+		}
+	}
+	if strings.TrimSpace(max) != "" {
+		bound, err := parseAssertionNumber(max)
+		if err != nil {
+			return false, err
+		}
+		if c := v.Cmp(bound); c > 0 || (c == 0 && !maxInclusive) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func parseAssertionNumber(s string) (*big.Float, error) {
+	f, _, err := big.ParseFloat(strings.TrimSpace(s), 10, 128, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a number", strings.TrimSpace(s))
+	}
+	return f, nil
+}
+
+// compare returns whether the result c of big.Float.Cmp satisfies the operator.
+func compare(c int, op string) bool {
+	switch op {
+	case ">=":
+		return c >= 0
+	case "<=":
+		return c <= 0
+	case "!=":
+		return c != 0
+	case ">":
+		return c > 0
+	case "<":
+		return c < 0
+	default:
+		return c == 0
+	}
+}
+
+func disableForAssertion(device *contract.Device, resourceName string) {
+	recoveryMutex.Lock()
+	failing, tracked := recovering[device.Name]
+	if device.OperatingState != contract.Disabled || tracked {
+		// a device disabled by hand isn't enabled again by the recovery
+		if common.CurrentConfig.Device.AssertionRecoveryReads > 0 {
+			if !tracked {
+				failing = make(map[string]int)
+				recovering[device.Name] = failing
+			}
+			failing[resourceName] = 0
+		}
+	}
+	recoveryMutex.Unlock()
+
+	if device.OperatingState != contract.Disabled {
+		updateOperatingState(device, contract.Disabled)
+	}
+}
+
+// recordAssertionPass counts the passing assertion of the DeviceResource if
+// it failed while the device was recovering, the device is enabled again once
+// all the failed DeviceResources passed enough consecutive assertions.
+func recordAssertionPass(device *contract.Device, resourceName string) {
+	recoveryMutex.Lock()
+	failing, ok := recovering[device.Name]
+	if !ok {
+		recoveryMutex.Unlock()
+		return
+	}
+	required := common.CurrentConfig.Device.AssertionRecoveryReads
+	if required <= 0 || device.OperatingState != contract.Disabled {
+		// the recovery has been turned off, or the device enabled by hand
+		delete(recovering, device.Name)
+		recoveryMutex.Unlock()
+		return
+	}
+	passes, failed := failing[resourceName]
+	if !failed {
+		// the assertions of the other DeviceResources don't tell whether
+		// the device recovered
+		recoveryMutex.Unlock()
+		return
+	}
+	failing[resourceName] = passes + 1
+	for _, n := range failing {
+		if n < required {
+			recoveryMutex.Unlock()
+			return
+		}
+	}
+	delete(recovering, device.Name)
+	recoveryMutex.Unlock()
+
+	common.LoggingClient.Info(fmt.Sprintf("device %s passed %d consecutive assertions of each failed DeviceResource, enabling it", device.Name, required))
+	updateOperatingState(device, contract.Enabled)
+}
+
+func updateOperatingState(device *contract.Device, state contract.OperatingState) {
+	device.OperatingState = state
+	cache.Devices().Update(*device)
+	ctx := context.WithValue(context.Background(), common.CorrelationHeader, uuid.New().String())
+	go common.DeviceClient.UpdateOpStateByName(device.Name, string(state), ctx)
+}
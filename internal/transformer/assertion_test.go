@@ -0,0 +1,193 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/cache"
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	"github.com/edgexfoundry/device-sdk-go/internal/mock"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAssertionResource(assertion string, policy string) *contract.DeviceResource {
+	dr := &contract.DeviceResource{
+		Name:       "resource",
+		Properties: contract.ProfileProperty{Value: contract.PropertyValue{Assertion: assertion}},
+	}
+	if policy != "" {
+		dr.Attributes = map[string]string{AssertionPolicyAttribute: policy}
+	}
+	return dr
+}
+
+func TestEvaluateAssertion(t *testing.T) {
+	int8Value, _ := dsModels.NewInt8Value("resource", 0, 12)
+	uint64Value, _ := dsModels.NewUint64Value("resource", 0, math.MaxUint64)
+	float64Value, _ := dsModels.NewFloat64Value("resource", 0, 1.5)
+	nanValue, _ := dsModels.NewFloat64Value("resource", 0, math.NaN())
+	boolValue, _ := dsModels.NewBoolValue("resource", 0, true)
+	stringValue := dsModels.NewStringValue("resource", 0, "running")
+
+	tests := []struct {
+		testName  string
+		cv        *dsModels.CommandValue
+		assertion string
+		expectErr bool
+	}{
+		{"Equal", int8Value, "12", false},
+		{"NotEqual", int8Value, "13", true},
+		{"EqualOperator", int8Value, "== 12", false},
+		{"NotEqualOperator", int8Value, "!=12", true},
+		{"GreaterOrEqual", int8Value, ">=12", false},
+		{"Greater", int8Value, ">12", true},
+		{"LessOrEqual", int8Value, "<=12", false},
+		{"Less", int8Value, "<12", true},
+		{"Uint64Maximum", uint64Value, ">=18446744073709551615", false},
+		{"Float", float64Value, ">1.25", false},
+		{"FloatEqual", float64Value, "1.5", false},
+		{"InclusiveRange", int8Value, "[0,12]", false},
+		{"ExclusiveRange", int8Value, "[0,12)", true},
+		{"RangeWithSpaces", int8Value, "( 0 , 100 ]", false},
+		{"LowerBoundOnly", int8Value, "[13,]", true},
+		{"UpperBoundOnly", float64Value, "(,1.5]", false},
+		{"InvalidOperand", int8Value, ">=ten", true},
+		{"InvalidRange", int8Value, "[a,b]", true},
+		{"NaN", nanValue, ">=0", true},
+		{"Bool", boolValue, "true", false},
+		{"BoolOperator", boolValue, "!=false", false},
+		{"String", stringValue, "running", false},
+		{"StringNotEqual", stringValue, "stopped", true},
+		{"StringNotEqualOperator", stringValue, "!= stopped", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := EvaluateAssertion(tt.cv, tt.assertion)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestAssertionPolicy(t *testing.T) {
+	common.CurrentConfig = &common.Config{Device: common.DeviceInfo{AssertionPolicy: "Warn"}}
+
+	tests := []struct {
+		testName string
+		policy   string
+		expected string
+	}{
+		{"Default", "", AssertionPolicyWarn},
+		{"Disable", "disable", AssertionPolicyDisable},
+		{"Drop", "DROP", AssertionPolicyDrop},
+		{"Unknown", "ignore", AssertionPolicyDisable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AssertionPolicy(newAssertionResource("1", tt.policy)))
+		})
+	}
+}
+
+func TestCheckAssertionRecovery(t *testing.T) {
+	common.ValueDescriptorClient = &mock.ValueDescriptorMock{}
+	common.ProvisionWatcherClient = &mock.ProvisionWatcherClientMock{}
+	common.DeviceClient = &mock.DeviceClientMock{}
+	cache.InitCache()
+	common.CurrentConfig = &common.Config{Device: common.DeviceInfo{AssertionRecoveryReads: 2}}
+
+	device := cache.Devices().All()[0]
+	device.OperatingState = contract.Enabled
+	_ = cache.Devices().Update(device)
+	operatingState := func() contract.OperatingState {
+		d, _ := cache.Devices().ForName(device.Name)
+		return d.OperatingState
+	}
+
+	passed, _ := dsModels.NewInt8Value("resource", 0, 50)
+	failed, _ := dsModels.NewInt8Value("resource", 0, -1)
+	dr := newAssertionResource("[0,100]", "")
+
+	policy, err := CheckAssertion(failed, newAssertionResource("[0,100]", AssertionPolicyWarn), &device)
+	assert.Equal(t, AssertionPolicyWarn, policy)
+	assert.Error(t, err)
+	assert.Equal(t, contract.OperatingState(contract.Enabled), operatingState(), "the warn policy shouldn't disable the device")
+
+	policy, err = CheckAssertion(failed, dr, &device)
+	assert.Equal(t, AssertionPolicyDisable, policy)
+	assert.Error(t, err)
+	assert.Equal(t, contract.OperatingState(contract.Disabled), operatingState())
+	assert.True(t, AssertionRecovering(device.Name))
+
+	// a failure resets the count of the passing assertions
+	_, err = CheckAssertion(passed, dr, &device)
+	assert.NoError(t, err)
+	_, _ = CheckAssertion(failed, dr, &device)
+	_, _ = CheckAssertion(passed, dr, &device)
+	assert.Equal(t, contract.OperatingState(contract.Disabled), operatingState())
+
+	_, _ = CheckAssertion(passed, dr, &device)
+	assert.Equal(t, contract.OperatingState(contract.Enabled), operatingState())
+	assert.False(t, AssertionRecovering(device.Name))
+}
+
+func TestCheckAssertionRecoveryMultipleResources(t *testing.T) {
+	common.ValueDescriptorClient = &mock.ValueDescriptorMock{}
+	common.ProvisionWatcherClient = &mock.ProvisionWatcherClientMock{}
+	common.DeviceClient = &mock.DeviceClientMock{}
+	cache.InitCache()
+	common.CurrentConfig = &common.Config{Device: common.DeviceInfo{AssertionRecoveryReads: 2}}
+
+	device := cache.Devices().All()[0]
+	device.OperatingState = contract.Enabled
+	_ = cache.Devices().Update(device)
+	operatingState := func() contract.OperatingState {
+		d, _ := cache.Devices().ForName(device.Name)
+		return d.OperatingState
+	}
+
+	temperature := newAssertionResource("[0,100]", "")
+	temperature.Name = "temperature"
+	humidity := newAssertionResource("[0,100]", "")
+	humidity.Name = "humidity"
+	pressure := newAssertionResource("[0,100]", "")
+	pressure.Name = "pressure"
+	read := func(name string, value int8) {
+		cv, _ := dsModels.NewInt8Value(name, 0, value)
+		dr := map[string]*contract.DeviceResource{"temperature": temperature, "humidity": humidity, "pressure": pressure}[name]
+		_, _ = CheckAssertion(cv, dr, &device)
+	}
+
+	// both temperature and humidity fail in the same read
+	read("temperature", -1)
+	read("humidity", -1)
+	read("pressure", 50)
+	assert.Equal(t, contract.OperatingState(contract.Disabled), operatingState())
+
+	// the passing assertions of temperature and of the resource which never
+	// failed don't recover the device while humidity still fails
+	for i := 0; i < 3; i++ {
+		read("temperature", 50)
+		read("humidity", -1)
+		read("pressure", 50)
+	}
+	assert.Equal(t, contract.OperatingState(contract.Disabled), operatingState())
+	assert.True(t, AssertionRecovering(device.Name))
+
+	read("temperature", 50)
+	read("humidity", 50)
+	assert.Equal(t, contract.OperatingState(contract.Disabled), operatingState())
+	read("temperature", 50)
+	read("humidity", 50)
+	assert.Equal(t, contract.OperatingState(contract.Enabled), operatingState())
+	assert.False(t, AssertionRecovering(device.Name))
+}
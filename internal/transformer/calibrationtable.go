@@ -0,0 +1,111 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// tableCache maps the source of the parsed calibration tables to their table.
+var tableCache sync.Map
+
+// calibrationTable is a piecewise-linear function defined by points sorted by
+// raw value. The calibrated values are strictly monotonic so that the table
+// can be inverted.
+type calibrationTable struct {
+	raw   []float64
+	value []float64
+}
+
+// parseCalibrationTable parses a table written as comma separated raw:value
+// points, e.g. "0:-40, 512:25, 1023:125".
+func parseCalibrationTable(source string) (*calibrationTable, error) {
+	if t, ok := tableCache.Load(source); ok {
+		return t.(*calibrationTable), nil
+	}
+
+	t := &calibrationTable{}
+	for _, point := range strings.Split(source, ",") {
+		fields := strings.Split(point, ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid calibration table %q: the point %q should be raw:value", source, strings.TrimSpace(point))
+		}
+		raw, err := strconv.ParseFloat(strings.TrimSpace(fields[0]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calibration table %q: the raw value %q is not a number", source, strings.TrimSpace(fields[0]))
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calibration table %q: the value %q is not a number", source, strings.TrimSpace(fields[1]))
+		}
+		t.raw = append(t.raw, raw)
+		t.value = append(t.value, value)
+	}
+
+	if len(t.raw) < 2 {
+		return nil, fmt.Errorf("invalid calibration table %q: at least two points are required", source)
+	}
+	increasing := t.value[1] > t.value[0]
+	for i := 1; i < len(t.raw); i++ {
+		if t.raw[i] <= t.raw[i-1] {
+			return nil, fmt.Errorf("invalid calibration table %q: the raw values should be strictly increasing", source)
+		}
+		if (t.value[i] > t.value[i-1]) != increasing || t.value[i] == t.value[i-1] {
+			return nil, fmt.Errorf("invalid calibration table %q: the values should be strictly monotonic", source)
+		}
+	}
+
+	tableCache.Store(source, t)
+	return t, nil
+}
+
+// read returns the calibrated value of the raw value x.
+func (t *calibrationTable) read(x float64) (float64, error) {
+	v, ok := interpolate(t.raw, t.value, x)
+	if !ok {
+		return 0, fmt.Errorf("raw value %v is outside the calibration table range [%v, %v]", x, t.raw[0], t.raw[len(t.raw)-1])
+	}
+	return v, nil
+}
+
+// inverse returns the raw value of the calibrated value y.
+func (t *calibrationTable) inverse(y float64) (float64, error) {
+	xs, ys := t.value, t.raw
+	if xs[0] > xs[len(xs)-1] {
+		// interpolate needs increasing xs
+		xs, ys = reversed(xs), reversed(ys)
+	}
+	v, ok := interpolate(xs, ys, y)
+	if !ok {
+		return 0, fmt.Errorf("value %v is outside the calibration table range [%v, %v]", y, xs[0], xs[len(xs)-1])
+	}
+	return v, nil
+}
+
+// interpolate returns the linear interpolation at x of the points (xs, ys),
+// ok is false if x is outside of xs. xs should be strictly increasing.
+func interpolate(xs []float64, ys []float64, x float64) (float64, bool) {
+	if !(x >= xs[0] && x <= xs[len(xs)-1]) {
+		return 0, false
+	}
+	i := 1
+	for i < len(xs)-1 && x > xs[i] {
+		i++
+	}
+	return ys[i-1] + (x-xs[i-1])*(ys[i]-ys[i-1])/(xs[i]-xs[i-1]), true
+}
+
+func reversed(a []float64) []float64 {
+	r := make([]float64, len(a))
+	for i, v := range a {
+		r[len(a)-1-i] = v
+	}
+	return r
+}
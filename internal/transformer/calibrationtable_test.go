@@ -0,0 +1,81 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseCalibrationTable(t *testing.T) {
+	tests := []struct {
+		testName  string
+		table     string
+		expectErr bool
+	}{
+		{"Increasing", "0:-40, 512:25, 1023:125", false},
+		{"Decreasing", "100:80, 200:40, 300:-10", false},
+		{"SinglePoint", "0:0", true},
+		{"NotAPoint", "0:0, 1", true},
+		{"InvalidRaw", "a:0, 1:1", true},
+		{"InvalidValue", "0:0, 1:b", true},
+		{"RawNotIncreasing", "0:0, 0:1", true},
+		{"ValueNotMonotonic", "0:0, 1:2, 2:1", true},
+		{"ValueConstant", "0:1, 1:1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := parseCalibrationTable(tt.table)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("%s expectErr:%v error:%v", tt.testName, tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestCalibrationTableReadInverse(t *testing.T) {
+	tests := []struct {
+		testName  string
+		table     string
+		raw       float64
+		value     float64
+		expectErr bool
+	}{
+		{"FirstPoint", "0:-40, 512:25, 1023:125", 0, -40, false},
+		{"FirstSegment", "0:-40, 512:25, 1023:125", 256, -7.5, false},
+		{"InnerPoint", "0:-40, 512:25, 1023:125", 512, 25, false},
+		{"LastPoint", "0:-40, 512:25, 1023:125", 1023, 125, false},
+		{"DecreasingSegment", "100:80, 200:40, 300:-10", 250, 15, false},
+		{"BelowRange", "0:-40, 512:25, 1023:125", -1, 0, true},
+		{"AboveRange", "100:80, 200:40, 300:-10", 301, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			table, err := parseCalibrationTable(tt.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := table.read(tt.raw)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expectErr:%v error:%v", tt.expectErr, err)
+			}
+			if tt.expectErr {
+				return
+			}
+			if math.Abs(value-tt.value) > 1e-9 {
+				t.Errorf("expected the value %v, got %v", tt.value, value)
+			}
+			raw, err := table.inverse(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(raw-tt.raw) > 1e-9 {
+				t.Errorf("expected the inverse %v, got %v", tt.raw, raw)
+			}
+		})
+	}
+}
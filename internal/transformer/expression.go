@@ -0,0 +1,364 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"unicode"
+)
+
+const (
+	maxExpressionLength = 256
+	maxExpressionDepth  = 32
+)
+
+// expressionFunctions are the only functions an expression can call, an
+// expression has no access to anything but its variable and these functions.
+var expressionFunctions = map[string]struct {
+	args int
+	fn   func(args []float64) float64
+}{
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"ln":    {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log10": {1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+// expressionCache maps the source and the variable of the parsed expressions
+// to their expression, so that an expression is parsed once.
+var expressionCache sync.Map
+
+// expression is an arithmetic expression of a single variable.
+type expression interface {
+	eval(x float64) (float64, error)
+	// affine returns a and b if the expression is a*x + b, ok is false otherwise.
+	affine() (a float64, b float64, ok bool)
+}
+
+type numberNode float64
+
+func (n numberNode) eval(float64) (float64, error) { return float64(n), nil }
+
+func (n numberNode) affine() (float64, float64, bool) { return 0, float64(n), true }
+
+type variableNode struct{}
+
+func (variableNode) eval(x float64) (float64, error) { return x, nil }
+
+func (variableNode) affine() (float64, float64, bool) { return 1, 0, true }
+
+type negateNode struct {
+	operand expression
+}
+
+func (n negateNode) eval(x float64) (float64, error) {
+	v, err := n.operand.eval(x)
+	return -v, err
+}
+
+func (n negateNode) affine() (float64, float64, bool) {
+	a, b, ok := n.operand.affine()
+	return -a, -b, ok
+}
+
+type binaryNode struct {
+	op          byte
+	left, right expression
+}
+
+func (n binaryNode) eval(x float64) (float64, error) {
+	l, err := n.left.eval(x)
+	if err != nil {
+		return 0, err
+	}
+	r, err := n.right.eval(x)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	case '/':
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	default:
+		return math.Pow(l, r), nil
+	}
+}
+
+func (n binaryNode) affine() (float64, float64, bool) {
+	la, lb, lok := n.left.affine()
+	ra, rb, rok := n.right.affine()
+	if !lok || !rok {
+		return 0, 0, false
+	}
+
+	switch n.op {
+	case '+':
+		return la + ra, lb + rb, true
+	case '-':
+		return la - ra, lb - rb, true
+	case '*':
+		if la == 0 {
+			return lb * ra, lb * rb, true
+		} else if ra == 0 {
+			return la * rb, lb * rb, true
+		}
+	case '/':
+		if ra == 0 && rb != 0 {
+			return la / rb, lb / rb, true
+		}
+	default:
+		if la == 0 && ra == 0 {
+			return 0, math.Pow(lb, rb), true
+		}
+	}
+	return 0, 0, false
+}
+
+type callNode struct {
+	name string
+	args []expression
+}
+
+func (n callNode) eval(x float64) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(x)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	return expressionFunctions[n.name].fn(args), nil
+}
+
+func (n callNode) affine() (float64, float64, bool) {
+	for _, arg := range n.args {
+		if a, _, ok := arg.affine(); !ok || a != 0 {
+			return 0, 0, false
+		}
+	}
+	// a call with constant arguments is a constant
+	v, err := n.eval(0)
+	return 0, v, err == nil
+}
+
+// parseExpression parses an arithmetic expression of the variable, made of
+// numbers, the + - * / ^ operators, parentheses and the expressionFunctions.
+func parseExpression(source string, variable string) (expression, error) {
+	key := variable + ":" + source
+	if e, ok := expressionCache.Load(key); ok {
+		return e.(expression), nil
+	}
+
+	if len(source) > maxExpressionLength {
+		return nil, fmt.Errorf("the expression is longer than %d characters", maxExpressionLength)
+	}
+	p := &expressionParser{source: source, variable: variable}
+	e, err := p.parseSum()
+	if err == nil && p.skipSpaces() < len(p.source) {
+		err = p.errorf("unexpected %q", p.source[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", source, err)
+	}
+
+	expressionCache.Store(key, e)
+	return e, nil
+}
+
+type expressionParser struct {
+	source   string
+	variable string
+	pos      int
+	depth    int
+}
+
+func (p *expressionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+// skipSpaces moves to the next character which isn't a space and returns its position.
+func (p *expressionParser) skipSpaces() int {
+	for p.pos < len(p.source) && unicode.IsSpace(rune(p.source[p.pos])) {
+		p.pos++
+	}
+	return p.pos
+}
+
+// next returns the next character which isn't a space, or 0 at the end.
+func (p *expressionParser) next() byte {
+	if p.skipSpaces() < len(p.source) {
+		return p.source[p.pos]
+	}
+	return 0
+}
+
+func (p *expressionParser) parseSum() (expression, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionDepth {
+		return nil, p.errorf("the expression is nested deeper than %d levels", maxExpressionDepth)
+	}
+
+	e, err := p.parseProduct()
+	for err == nil && (p.next() == '+' || p.next() == '-') {
+		op := p.source[p.pos]
+		p.pos++
+		var right expression
+		right, err = p.parseProduct()
+		e = binaryNode{op: op, left: e, right: right}
+	}
+	return e, err
+}
+
+func (p *expressionParser) parseProduct() (expression, error) {
+	e, err := p.parseUnary()
+	for err == nil && (p.next() == '*' || p.next() == '/') {
+		op := p.source[p.pos]
+		p.pos++
+		var right expression
+		right, err = p.parseUnary()
+		e = binaryNode{op: op, left: e, right: right}
+	}
+	return e, err
+}
+
+func (p *expressionParser) parseUnary() (expression, error) {
+	switch p.next() {
+	case '-':
+		p.pos++
+		e, err := p.parseUnary()
+		return negateNode{operand: e}, err
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	}
+
+	e, err := p.parsePrimary()
+	if err == nil && p.next() == '^' {
+		// ^ is right-associative and binds tighter than the unary minus
+		p.pos++
+		var exponent expression
+		exponent, err = p.parseUnary()
+		e = binaryNode{op: '^', left: e, right: exponent}
+	}
+	return e, err
+}
+
+func (p *expressionParser) parsePrimary() (expression, error) {
+	c := p.next()
+	switch {
+	case c == '(':
+		p.pos++
+		e, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ')' {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case c == '_' || unicode.IsLetter(rune(c)):
+		return p.parseIdentifier()
+	case c == 0:
+		return nil, p.errorf("unexpected end of the expression")
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *expressionParser) parseNumber() (expression, error) {
+	start := p.pos
+	for p.pos < len(p.source) && (p.source[p.pos] == '.' || (p.source[p.pos] >= '0' && p.source[p.pos] <= '9')) {
+		p.pos++
+	}
+	// exponent, e.g. 1.5e-3
+	if p.pos < len(p.source) && (p.source[p.pos] == 'e' || p.source[p.pos] == 'E') {
+		end := p.pos + 1
+		if end < len(p.source) && (p.source[end] == '+' || p.source[end] == '-') {
+			end++
+		}
+		if end < len(p.source) && p.source[end] >= '0' && p.source[end] <= '9' {
+			for end < len(p.source) && p.source[end] >= '0' && p.source[end] <= '9' {
+				end++
+			}
+			p.pos = end
+		}
+	}
+
+	number := p.source[start:p.pos]
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number %s", number)
+	}
+	return numberNode(v), nil
+}
+
+func (p *expressionParser) parseIdentifier() (expression, error) {
+	start := p.pos
+	for p.pos < len(p.source) {
+		c := rune(p.source[p.pos])
+		if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			break
+		}
+		p.pos++
+	}
+	name := p.source[start:p.pos]
+
+	if name == p.variable {
+		return variableNode{}, nil
+	}
+	f, ok := expressionFunctions[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown identifier %s", name)
+	}
+
+	if p.next() != '(' {
+		return nil, p.errorf("missing ( after %s", name)
+	}
+	p.pos++
+	var args []expression
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.next() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if p.next() != ')' {
+		return nil, p.errorf("missing ) after the arguments of %s", name)
+	}
+	p.pos++
+	if len(args) != f.args {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", name, f.args, len(args))
+	}
+	return callNode{name: name, args: args}, nil
+}
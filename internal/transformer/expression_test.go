@@ -0,0 +1,93 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		raw        float64
+		expected   float64
+		expectErr  bool
+	}{
+		{"(raw * 0.1) - 40", 650, 25, false},
+		{"raw*0.1-40", 650, 25, false},
+		{"-raw + 1", 2, -1, false},
+		{"2 ^ 3 ^ 2", 0, 512, false},
+		{"-2 ^ 2", 0, -4, false},
+		{"raw / 4 * 2", 8, 4, false},
+		{"1.5e2 + raw", 1, 151, false},
+		{"abs(raw) + sqrt(16)", -2, 6, false},
+		{"max(raw, 10) + min(raw, 10)", 3, 13, false},
+		{"ln(exp(raw))", 2, 2, false},
+		{"log10(raw)", 1000, 3, false},
+		{"raw / 0", 1, 0, true},
+		{"", 0, 0, true},
+		{"raw +", 0, 0, true},
+		{"(raw", 0, 0, true},
+		{"raw)", 0, 0, true},
+		{"1..2", 0, 0, true},
+		{"x * 2", 0, 0, true},
+		{"os.Exit(1)", 0, 0, true},
+		{"max(raw)", 0, 0, true},
+		{"sqrt raw", 0, 0, true},
+		{strings.Repeat("(", 40) + "raw" + strings.Repeat(")", 40), 0, 0, true},
+		{strings.Repeat("raw+", 100) + "raw", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := parseExpression(tt.expression, expressionVariable)
+			var v float64
+			if err == nil {
+				v, err = e.eval(tt.raw)
+			}
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("expectErr:%v error:%v", tt.expectErr, err)
+			}
+			if err == nil && math.Abs(v-tt.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, v)
+			}
+		})
+	}
+}
+
+func TestExpressionAffine(t *testing.T) {
+	tests := []struct {
+		expression string
+		a, b       float64
+		ok         bool
+	}{
+		{"(raw * 0.1) - 40", 0.1, -40, true},
+		{"2 * (raw + 1) / 4", 0.5, 0.5, true},
+		{"-(raw - 3)", -1, 3, true},
+		{"raw * 2 ^ 3", 8, 0, true},
+		{"raw * sqrt(4)", 2, 0, true},
+		{"raw * raw", 0, 0, false},
+		{"1 / raw", 0, 0, false},
+		{"ln(raw)", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			e, err := parseExpression(tt.expression, expressionVariable)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, b, ok := e.affine()
+			if ok != tt.ok {
+				t.Fatalf("expected affine %v, got %v", tt.ok, ok)
+			}
+			if ok && (math.Abs(a-tt.a) > 1e-9 || math.Abs(b-tt.b) > 1e-9) {
+				t.Errorf("expected %v*raw + %v, got %v*raw + %v", tt.a, tt.b, a, b)
+			}
+		})
+	}
+}
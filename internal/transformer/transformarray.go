@@ -0,0 +1,243 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+)
+
+func isNumericArray(t dsModels.ValueType) bool {
+	switch t {
+	case dsModels.Uint8Array, dsModels.Uint16Array, dsModels.Uint32Array, dsModels.Uint64Array,
+		dsModels.Int8Array, dsModels.Int16Array, dsModels.Int32Array, dsModels.Int64Array,
+		dsModels.Float32Array, dsModels.Float64Array:
+		return true
+	}
+	return false
+}
+
+// NumericArrayValues returns the elements of a numeric array CommandValue, ok
+// is false if cv is not a numeric array.
+func NumericArrayValues(cv *dsModels.CommandValue) (values []*big.Float, ok bool, err error) {
+	if !isNumericArray(cv.Type) {
+		return nil, false, nil
+	}
+	elements, err := arrayValuesForTransform(cv)
+	if err != nil {
+		return nil, false, err
+	}
+
+	values = make([]*big.Float, len(elements))
+	for i, element := range elements {
+		v := new(big.Float).SetPrec(128)
+		switch n := element.(type) {
+		case uint8:
+			v.SetUint64(uint64(n))
+		case uint16:
+			v.SetUint64(uint64(n))
+		case uint32:
+			v.SetUint64(uint64(n))
+		case uint64:
+			v.SetUint64(n)
+		case int8:
+			v.SetInt64(int64(n))
+		case int16:
+			v.SetInt64(int64(n))
+		case int32:
+			v.SetInt64(int64(n))
+		case int64:
+			v.SetInt64(n)
+		case float32:
+			if math.IsNaN(float64(n)) {
+				return nil, false, fmt.Errorf("element %d of DeviceResource %s is not a number", i, cv.DeviceResourceName)
+			}
+			v.SetFloat64(float64(n))
+		case float64:
+			if math.IsNaN(n) {
+				return nil, false, fmt.Errorf("element %d of DeviceResource %s is not a number", i, cv.DeviceResourceName)
+			}
+			v.SetFloat64(n)
+		}
+		values[i] = v
+	}
+	return values, true, nil
+}
+
+// transformReadArray applies the scale and offset of the PropertyValue to
+// every element of the array.
+func transformReadArray(cv *dsModels.CommandValue, pv contract.PropertyValue) error {
+	values, err := arrayValuesForTransform(cv)
+	if err != nil {
+		return err
+	}
+
+	for i, value := range values {
+		if pv.Scale != "" && pv.Scale != defaultScale {
+			value, err = transformReadScale(value, pv.Scale)
+			if overflowError, ok := err.(OverflowError); ok {
+				return errors.Wrap(overflowError, fmt.Sprintf("Overflow failed for element %d of device resource '%v' ", i, cv.DeviceResourceName))
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if pv.Offset != "" && pv.Offset != defaultOffset {
+			value, err = transformReadOffset(value, pv.Offset)
+			if overflowError, ok := err.(OverflowError); ok {
+				return errors.Wrap(overflowError, fmt.Sprintf("Overflow failed for element %d of device resource '%v' ", i, cv.DeviceResourceName))
+			} else if err != nil {
+				return err
+			}
+		}
+		values[i] = value
+	}
+
+	return replaceNewArrayCommandValue(cv, values)
+}
+
+// transformWriteArray applies the inverse of the offset and scale of the
+// PropertyValue to every element of the array, in the reverse order of
+// transformReadArray.
+func transformWriteArray(cv *dsModels.CommandValue, pv contract.PropertyValue) error {
+	values, err := arrayValuesForTransform(cv)
+	if err != nil {
+		return err
+	}
+
+	for i, value := range values {
+		if pv.Offset != "" && pv.Offset != defaultOffset {
+			value, err = transformWriteOffset(value, pv.Offset)
+			if overflowError, ok := err.(OverflowError); ok {
+				return errors.Wrap(overflowError, fmt.Sprintf("Overflow failed for element %d of device resource '%v' ", i, cv.DeviceResourceName))
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if pv.Scale != "" && pv.Scale != defaultScale {
+			value, err = transformWriteScale(value, pv.Scale)
+			if overflowError, ok := err.(OverflowError); ok {
+				return errors.Wrap(overflowError, fmt.Sprintf("Overflow failed for element %d of device resource '%v' ", i, cv.DeviceResourceName))
+			} else if err != nil {
+				return err
+			}
+		}
+		values[i] = value
+	}
+
+	return replaceNewArrayCommandValue(cv, values)
+}
+
+func arrayValuesForTransform(cv *dsModels.CommandValue) ([]interface{}, error) {
+	var values []interface{}
+	switch cv.Type {
+	case dsModels.Uint8Array:
+		a, err := cv.Uint8ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Uint16Array:
+		a, err := cv.Uint16ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Uint32Array:
+		a, err := cv.Uint32ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Uint64Array:
+		a, err := cv.Uint64ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Int8Array:
+		a, err := cv.Int8ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Int16Array:
+		a, err := cv.Int16ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Int32Array:
+		a, err := cv.Int32ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Int64Array:
+		a, err := cv.Int64ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Float32Array:
+		a, err := cv.Float32ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	case dsModels.Float64Array:
+		a, err := cv.Float64ArrayValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range a {
+			values = append(values, v)
+		}
+	default:
+		return nil, fmt.Errorf("wrong data type of CommandValue to transform: %s", cv.String())
+	}
+	return values, nil
+}
+
+func replaceNewArrayCommandValue(cv *dsModels.CommandValue, values []interface{}) error {
+	buf := new(bytes.Buffer)
+	for _, v := range values {
+		if err := binary.Write(buf, binary.BigEndian, v); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("binary.Write failed: %v", err))
+			return err
+		}
+	}
+	cv.NumericValue = buf.Bytes()
+	return nil
+}
@@ -0,0 +1,77 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"reflect"
+	"testing"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
+func TestTransformReadResult_array(t *testing.T) {
+	cv, _ := dsModels.NewInt16ArrayValue("test-object", 0, []int16{1, -2, 3})
+	pv := contract.PropertyValue{Scale: "10", Offset: "5"}
+
+	if err := TransformReadResult(cv, pv); err != nil {
+		t.Fatalf("Fail to transform read result, error: %v", err)
+	}
+	result, err := cv.Int16ArrayValue()
+	if err != nil {
+		t.Fatalf("Fail to transform read result, error: %v", err)
+	}
+	if expected := []int16{15, -15, 35}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unexpect test result, result '%v' should be '%v'", result, expected)
+	}
+	if cv.Type != dsModels.Int16Array {
+		t.Fatalf("Unexpect test result, value type '%v' should be '%v'", cv.Type, dsModels.Int16Array)
+	}
+}
+
+func TestTransformReadResult_array_overflow(t *testing.T) {
+	cv, _ := dsModels.NewUint8ArrayValue("test-object", 0, []uint8{1, 100})
+	pv := contract.PropertyValue{Scale: "3"}
+
+	if err := TransformReadResult(cv, pv); err == nil {
+		t.Fatal("Expect an overflow error for the second element")
+	}
+	result, _ := cv.Uint8ArrayValue()
+	if expected := []uint8{1, 100}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("The value should not be changed on error, result '%v' should be '%v'", result, expected)
+	}
+}
+
+func TestTransformWriteParameter_array(t *testing.T) {
+	cv, _ := dsModels.NewFloat64ArrayValue("test-object", 0, []float64{15, 25})
+	pv := contract.PropertyValue{Scale: "10", Offset: "5"}
+
+	if err := TransformWriteParameter(cv, pv); err != nil {
+		t.Fatalf("Fail to transform write parameter, error: %v", err)
+	}
+	result, _ := cv.Float64ArrayValue()
+	if expected := []float64{1, 2}; !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unexpect test result, result '%v' should be '%v'", result, expected)
+	}
+
+	boolArray, _ := dsModels.NewBoolArrayValue("test-object", 0, []bool{true})
+	if err := TransformWriteParameter(boolArray, pv); err != nil {
+		t.Fatalf("BoolArray should not be transformed, error: %v", err)
+	}
+}
+
+func TestTransformReadResult_object(t *testing.T) {
+	cv, _ := dsModels.NewObjectValue("test-object", 0, map[string]interface{}{"value": 1})
+	pv := contract.PropertyValue{Scale: "10", Offset: "5"}
+
+	if err := TransformReadResult(cv, pv); err != nil {
+		t.Fatalf("Object should not be transformed, error: %v", err)
+	}
+	if str := cv.ValueToString(); str != `{"value":1}` {
+		t.Fatalf("Object should not be transformed, got %s", str)
+	}
+}
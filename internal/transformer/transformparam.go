@@ -1,6 +1,6 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
-// Copyright (C) 2018-2019 IOTech Ltd
+// Copyright (C) 2018-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -9,273 +9,336 @@ package transformer
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"strconv"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
 )
 
+// TransformWriteParameter applies the inverse of the transforms of TransformReadResult
+// in the reverse order: offset, scale, base, then shift. An OverflowError is returned
+// if a result is out of the range of the type of cv. The bits outside of the mask are
+// set by MergeMaskedValue, since they are those of the current value of the device.
 func TransformWriteParameter(cv *dsModels.CommandValue, pv contract.PropertyValue) error {
-	var err error
-	if cv.Type == dsModels.String || cv.Type == dsModels.Bool || cv.Type == dsModels.Binary {
-		return nil // do nothing for String, Bool and Binary
+	if cv.Type == dsModels.String || cv.Type == dsModels.Bool || cv.Type == dsModels.Binary ||
+		cv.Type == dsModels.BoolArray || cv.Type == dsModels.Object {
+		return nil // do nothing for String, Bool, Binary, BoolArray and Object
+	} else if isNumericArray(cv.Type) {
+		return transformWriteArray(cv, pv)
 	}
 
 	value, err := commandValueForTransform(cv)
-	newValue := value
+	if err != nil {
+		return err
+	}
 
+	newValue, err := transformWriteValue(value, pv)
+	if overflowError, ok := err.(OverflowError); ok {
+		return errors.Wrap(overflowError, fmt.Sprintf("Overflow failed for device resource '%v' ", cv.DeviceResourceName))
+	} else if err != nil {
+		return err
+	}
+
+	if value != newValue {
+		err = replaceNewCommandValue(cv, newValue)
+	}
+	return err
+}
+
+func transformWriteValue(value interface{}, pv contract.PropertyValue) (interface{}, error) {
+	var err error
 	if pv.Offset != "" && pv.Offset != defaultOffset {
-		newValue, err = transformWriteOffset(newValue, pv.Offset)
-		if err != nil {
-			return err
+		if value, err = transformWriteOffset(value, pv.Offset); err != nil {
+			return value, err
 		}
 	}
 
 	if pv.Scale != "" && pv.Scale != defaultScale {
-		newValue, err = transformWriteScale(newValue, pv.Scale)
-		if err != nil {
-			return err
+		if value, err = transformWriteScale(value, pv.Scale); err != nil {
+			return value, err
 		}
 	}
 
 	if pv.Base != "" && pv.Base != defaultBase {
-		newValue, err = transformWriteBase(newValue, pv.Base)
+		if value, err = transformWriteBase(value, pv.Base); err != nil {
+			return value, err
+		}
 	}
 
-	if value != newValue {
-		err = replaceNewCommandValue(cv, newValue)
+	if !isUnsigned(value) {
+		return value, nil
 	}
-	return err
+
+	if pv.Shift != "" && pv.Shift != defaultShift {
+		if value, err = transformWriteShift(value, pv.Shift); err != nil {
+			return value, err
+		}
+	}
+
+	if pv.Mask != "" && pv.Mask != defaultMask {
+		m, err := strconv.ParseUint(pv.Mask, 10, 64)
+		if err != nil {
+			return value, fmt.Errorf("invalid mask value, the mask %s should be unsigned and parsed to %T. %v", pv.Mask, m, err)
+		}
+		if v := toUint64(value); v&^m != 0 {
+			return value, fmt.Errorf("the value %d doesn't fit in the bits of the mask %s", v, pv.Mask)
+		}
+	}
+	return value, nil
 }
 
+// MaskedWrite returns whether the written value only sets the bits of the mask
+// of the PropertyValue, in which case the written value should be merged with
+// the current value of the device by MergeMaskedValue.
+func MaskedWrite(cv *dsModels.CommandValue, pv contract.PropertyValue) bool {
+	if pv.Mask == "" || pv.Mask == defaultMask {
+		return false
+	}
+	switch cv.Type {
+	case dsModels.Uint8, dsModels.Uint16, dsModels.Uint32, dsModels.Uint64:
+		return true
+	}
+	return false
+}
+
+// MergeMaskedValue replaces the value of cv with the current value, in which
+// the bits of the mask are set to those of cv.
+func MergeMaskedValue(cv *dsModels.CommandValue, current *dsModels.CommandValue, pv contract.PropertyValue) error {
+	if cv.Type != current.Type {
+		return fmt.Errorf("the current value of device resource '%v' is %v, %v is expected", cv.DeviceResourceName, current.Type, cv.Type)
+	}
+	m, err := strconv.ParseUint(pv.Mask, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid mask value, the mask %s should be unsigned and parsed to %T. %v", pv.Mask, m, err)
+	}
+
+	value, err := commandValueForTransform(cv)
+	if err != nil {
+		return err
+	}
+	currentValue, err := commandValueForTransform(current)
+	if err != nil {
+		return err
+	}
+
+	merged := toUint64(currentValue)&^m | toUint64(value)&m
+	return replaceNewCommandValue(cv, fromUint64(value, merged))
+}
+
+// transformWriteBase returns the logarithm in the base of the value, the
+// inverse of raising the base to the power of the value.
 func transformWriteBase(value interface{}, base string) (interface{}, error) {
 	b, err := strconv.ParseFloat(base, 64)
 	if err != nil {
-		common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to float64: %v", base, err))
+		common.LoggingClient.Error(fmt.Sprintf("the base %s of PropertyValue cannot be parsed to float64: %v", base, err))
 		return value, err
 	} else if b == 0 {
 		return value, nil // do nothing if Base = 0
 	}
 
-	var valueFloat64 float64
-	switch v := value.(type) {
-	case uint8:
-		valueFloat64 = float64(v)
-	case uint16:
-		valueFloat64 = float64(v)
-	case uint32:
-		valueFloat64 = float64(v)
-	case uint64:
-		valueFloat64 = float64(v)
-	case int8:
-		valueFloat64 = float64(v)
-	case int16:
-		valueFloat64 = float64(v)
-	case int32:
-		valueFloat64 = float64(v)
-	case int64:
-		valueFloat64 = float64(v)
-	case float32:
-		valueFloat64 = float64(v)
-	case float64:
-		valueFloat64 = v
-	}
+	return fromFloat64Checked(value, math.Log(toFloat64(value))/math.Log(b))
+}
 
-	// inverse of a base transform for a value
-	valueFloat64 = math.Log(valueFloat64) / math.Log(b)
+func transformWriteScale(value interface{}, scale string) (interface{}, error) {
+	bitSize := 64
+	if _, ok := value.(float32); ok {
+		bitSize = 32
+	}
+	s, err := strconv.ParseFloat(scale, bitSize)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, value, err))
+		return value, err
+	} else if s == 0 {
+		return value, fmt.Errorf("the scale %s of PropertyValue cannot be inverted", scale)
+	}
 
-	switch value.(type) {
-	case uint8:
-		value = uint8(valueFloat64)
-	case uint16:
-		value = uint16(valueFloat64)
-	case uint32:
-		value = uint32(valueFloat64)
-	case uint64:
-		value = uint64(valueFloat64)
-	case int8:
-		value = int8(valueFloat64)
-	case int16:
-		value = int16(valueFloat64)
-	case int32:
-		value = int32(valueFloat64)
-	case int64:
-		value = int64(valueFloat64)
+	switch v := value.(type) {
 	case float32:
-		value = float32(valueFloat64)
+		return fromFloat64Checked(value, float64(v/float32(s)))
 	case float64:
-		value = valueFloat64
+		return fromFloat64Checked(value, v/s)
 	}
-	return value, err
+	return fromFloat64Checked(value, toFloat64(value)/s)
 }
 
-func transformWriteScale(value interface{}, scale string) (interface{}, error) {
+func transformWriteOffset(value interface{}, offset string) (interface{}, error) {
 	switch v := value.(type) {
-	case uint8:
-		s, err := strconv.ParseFloat(scale, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
-			return value, err
-		}
-		nv := float64(v)
-		value = uint8(nv / s)
-	case uint16:
-		s, err := strconv.ParseFloat(scale, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
-			return value, err
-		}
-		nv := float64(v)
-		value = uint16(nv / s)
-	case uint32:
-		s, err := strconv.ParseFloat(scale, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
-			return value, err
-		}
-		nv := float64(v)
-		value = uint32(nv / s)
-	case uint64:
-		s, err := strconv.ParseFloat(scale, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
-			return value, err
-		}
-		nv := float64(v)
-		value = uint64(nv / s)
-	case int8:
-		s, err := strconv.ParseFloat(scale, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
-			return value, err
-		}
-		nv := float64(v)
-		value = int8(nv / s)
-	case int16:
-		s, err := strconv.ParseFloat(scale, 64)
+	case float32:
+		o, err := strconv.ParseFloat(offset, 32)
 		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
+			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
 			return value, err
 		}
-		nv := float64(v)
-		value = int16(nv / s)
-	case int32:
-		s, err := strconv.ParseFloat(scale, 64)
+		return fromFloat64Checked(value, float64(v-float32(o)))
+	case float64:
+		o, err := strconv.ParseFloat(offset, 64)
 		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
+			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
 			return value, err
 		}
-		nv := float64(v)
-		value = int32(nv / s)
-	case int64:
-		s, err := strconv.ParseFloat(scale, 64)
+		return fromFloat64Checked(value, v-o)
+	}
+
+	// the integers are subtracted exactly, the offset is parsed as in transformReadOffset
+	o := new(big.Int)
+	if isUnsigned(value) {
+		n, err := strconv.ParseUint(offset, 10, bitSize(value))
 		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
+			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, value, err))
 			return value, err
 		}
-		nv := float64(v)
-		value = int64(nv / s)
-	case float32:
-		s, err := strconv.ParseFloat(scale, 32)
+		o.SetUint64(n)
+	} else {
+		n, err := strconv.ParseInt(offset, 10, bitSize(value))
 		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
+			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, value, err))
 			return value, err
 		}
-		ns := float32(s)
-		value = v / ns
-	case float64:
-		s, err := strconv.ParseFloat(scale, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the scale %s of PropertyValue cannot be parsed to %T: %v", scale, v, err))
-			return value, err
+		o.SetInt64(n)
+	}
+	return fromBigIntChecked(value, new(big.Int).Sub(toBigInt(value), o))
+}
+
+// transformWriteShift shifts the value in the opposite direction of
+// transformReadShift, the bits shifted out must be zero.
+func transformWriteShift(value interface{}, shift string) (interface{}, error) {
+	signed, err := isSignedNumber(shift)
+	if err != nil {
+		return value, err
+	}
+	s, err := strconv.ParseInt(shift, 10, 64)
+	if err != nil {
+		common.LoggingClient.Error(fmt.Sprintf("the shift %s of PropertyValue cannot be parsed to %T: %v", shift, s, err))
+		return value, err
+	}
+
+	v := toBigInt(value)
+	var shifted *big.Int
+	if signed {
+		// a negative shift is a right shift on read
+		shifted = new(big.Int).Lsh(v, uint(-s))
+	} else {
+		shifted = new(big.Int).Rsh(v, uint(s))
+		if new(big.Int).Lsh(shifted, uint(s)).Cmp(v) != 0 {
+			return value, fmt.Errorf("the value %v cannot be shifted right by %d bits without losing bits", value, s)
 		}
-		value = v / s
 	}
+	return fromBigIntChecked(value, shifted)
+}
 
-	return value, nil
+func isUnsigned(value interface{}) bool {
+	switch value.(type) {
+	case uint8, uint16, uint32, uint64:
+		return true
+	}
+	return false
 }
 
-func transformWriteOffset(value interface{}, offset string) (interface{}, error) {
+func bitSize(value interface{}) int {
+	switch value.(type) {
+	case uint8, int8:
+		return 8
+	case uint16, int16:
+		return 16
+	case uint32, int32, float32:
+		return 32
+	}
+	return 64
+}
+
+func toUint64(value interface{}) uint64 {
 	switch v := value.(type) {
 	case uint8:
-		o, err := strconv.ParseUint(offset, 10, 8)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := uint8(o)
-		value = v - no
+		return uint64(v)
 	case uint16:
-		o, err := strconv.ParseUint(offset, 10, 16)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := uint16(o)
-		value = v - no
+		return uint64(v)
 	case uint32:
-		o, err := strconv.ParseUint(offset, 10, 32)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := uint32(o)
-		value = v - no
+		return uint64(v)
 	case uint64:
-		o, err := strconv.ParseUint(offset, 10, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		value = v - o
+		return v
+	}
+	return 0
+}
+
+func fromUint64(value interface{}, u uint64) interface{} {
+	switch value.(type) {
+	case uint8:
+		return uint8(u)
+	case uint16:
+		return uint16(u)
+	case uint32:
+		return uint32(u)
+	}
+	return u
+}
+
+func toBigInt(value interface{}) *big.Int {
+	if isUnsigned(value) {
+		return new(big.Int).SetUint64(toUint64(value))
+	}
+	switch v := value.(type) {
 	case int8:
-		o, err := strconv.ParseInt(offset, 10, 8)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := int8(o)
-		value = v - no
+		return big.NewInt(int64(v))
 	case int16:
-		o, err := strconv.ParseInt(offset, 10, 16)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := int16(o)
-		value = v - no
+		return big.NewInt(int64(v))
 	case int32:
-		o, err := strconv.ParseInt(offset, 10, 32)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := int32(o)
-		value = v - no
+		return big.NewInt(int64(v))
 	case int64:
-		o, err := strconv.ParseInt(offset, 10, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		value = v - o
-	case float32:
-		o, err := strconv.ParseFloat(offset, 32)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
-		}
-		no := float32(o)
-		value = v - no
-	case float64:
-		o, err := strconv.ParseFloat(offset, 64)
-		if err != nil {
-			common.LoggingClient.Error(fmt.Sprintf("the offset %s of PropertyValue cannot be parsed to %T: %v", offset, v, err))
-			return value, err
+		return big.NewInt(v)
+	}
+	return new(big.Int)
+}
+
+// fromBigIntChecked converts i to the integer type of value, an OverflowError
+// is returned if i is out of the range of the type.
+func fromBigIntChecked(value interface{}, i *big.Int) (interface{}, error) {
+	bits := bitSize(value)
+	if isUnsigned(value) {
+		if i.Sign() < 0 || i.BitLen() > bits {
+			f, _ := new(big.Float).SetInt(i).Float64()
+			return value, NewOverflowError(value, f)
 		}
-		value = v - o
+		return fromUint64(value, i.Uint64()), nil
 	}
 
-	return value, nil
+	min := new(big.Int).Lsh(big.NewInt(-1), uint(bits-1))
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits-1)), big.NewInt(1))
+	if i.Cmp(min) < 0 || i.Cmp(max) > 0 {
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return value, NewOverflowError(value, f)
+	}
+	switch value.(type) {
+	case int8:
+		return int8(i.Int64()), nil
+	case int16:
+		return int16(i.Int64()), nil
+	case int32:
+		return int32(i.Int64()), nil
+	}
+	return i.Int64(), nil
+}
+
+// fromFloat64Checked converts f to the type of value, rounding f to the
+// nearest integer for the integer types. An OverflowError is returned if f is
+// out of the range of the type.
+func fromFloat64Checked(value interface{}, f float64) (interface{}, error) {
+	if isInteger(value) {
+		f = math.Round(f)
+	}
+	inRange := checkTransformedValueInRange(value, f)
+	// MaxInt64 and MaxUint64 round up to 2^63 and 2^64 as float64, which
+	// checkTransformedValueInRange lets through but don't fit in the types
+	switch value.(type) {
+	case int64:
+		inRange = inRange && f < 1<<63
+	case uint64:
+		inRange = inRange && f < 1<<64
+	}
+	if !inRange {
+		return value, NewOverflowError(value, f)
+	}
+	return fromFloat64(value, f), nil
 }
@@ -0,0 +1,256 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const roundTrips = 1000
+
+func newTransformValue(t *testing.T, valueType dsModels.ValueType, value interface{}) *dsModels.CommandValue {
+	cv := &dsModels.CommandValue{DeviceResourceName: "test-object", Type: valueType}
+	if err := replaceNewCommandValue(cv, value); err != nil {
+		t.Fatalf("Fail to create the CommandValue of %v: %v", value, err)
+	}
+	return cv
+}
+
+func transformValue(t *testing.T, cv *dsModels.CommandValue) interface{} {
+	v, err := commandValueForTransform(cv)
+	if err != nil {
+		t.Fatalf("Fail to read the CommandValue: %v", err)
+	}
+	return v
+}
+
+// TestTransformWriteParameter_roundTrip checks that writing the result of the
+// read transforms of a raw value, then merging it with the raw value for the
+// masked resources, writes the raw value back.
+func TestTransformWriteParameter_roundTrip(t *testing.T) {
+	tests := []struct {
+		testName  string
+		valueType dsModels.ValueType
+		pv        contract.PropertyValue
+		raw       func(r *rand.Rand) interface{}
+	}{
+		{"Uint8MaskShiftScaleOffset", dsModels.Uint8, contract.PropertyValue{Mask: "60", Shift: "-2", Scale: "3", Offset: "5"},
+			func(r *rand.Rand) interface{} { return uint8(r.Uint32()) }},
+		{"Uint16MaskShiftScaleOffset", dsModels.Uint16, contract.PropertyValue{Mask: "4080", Shift: "-4", Scale: "2", Offset: "10"},
+			func(r *rand.Rand) interface{} { return uint16(r.Uint32()) }},
+		{"Uint32MaskLeftShift", dsModels.Uint32, contract.PropertyValue{Mask: "65535", Shift: "4"},
+			func(r *rand.Rand) interface{} { return r.Uint32() }},
+		{"Uint64MaskLeftShiftOffset", dsModels.Uint64, contract.PropertyValue{Mask: "4294967295", Shift: "16", Offset: "1000"},
+			func(r *rand.Rand) interface{} { return r.Uint64() }},
+		{"Uint8Base", dsModels.Uint8, contract.PropertyValue{Base: "2"},
+			func(r *rand.Rand) interface{} { return uint8(r.Intn(8)) }},
+		{"Int8ScaleOffset", dsModels.Int8, contract.PropertyValue{Scale: "2", Offset: "-3"},
+			func(r *rand.Rand) interface{} { return int8(r.Uint32()) }},
+		{"Int16Offset", dsModels.Int16, contract.PropertyValue{Offset: "1000"},
+			func(r *rand.Rand) interface{} { return int16(r.Uint32()) }},
+		{"Int32ScaleOffset", dsModels.Int32, contract.PropertyValue{Scale: "-4", Offset: "7"},
+			func(r *rand.Rand) interface{} { return int32(r.Uint32()) }},
+		{"Int64Offset", dsModels.Int64, contract.PropertyValue{Offset: "-9000"},
+			func(r *rand.Rand) interface{} { return int64(r.Uint64()) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			transformed := 0
+			for i := 0; i < roundTrips; i++ {
+				raw := tt.raw(r)
+				cv := newTransformValue(t, tt.valueType, raw)
+				if err := TransformReadResult(cv, tt.pv); err != nil {
+					if _, ok := errors.Cause(err).(OverflowError); !ok {
+						t.Fatalf("Fail to transform read result of %v, error: %v", raw, err)
+					}
+					continue // the raw value can't be read
+				}
+				read := transformValue(t, cv)
+
+				if err := TransformWriteParameter(cv, tt.pv); err != nil {
+					t.Fatalf("Fail to transform write parameter %v read from %v, error: %v", read, raw, err)
+				}
+				if MaskedWrite(cv, tt.pv) {
+					if err := MergeMaskedValue(cv, newTransformValue(t, tt.valueType, raw), tt.pv); err != nil {
+						t.Fatalf("Fail to merge the masked value, error: %v", err)
+					}
+				}
+				if written := transformValue(t, cv); written != raw {
+					t.Fatalf("Unexpect test result, %v read from %v is written as %v", read, raw, written)
+				}
+				transformed++
+			}
+			assert.NotZero(t, transformed, "no raw value could be read")
+		})
+	}
+}
+
+func TestTransformWriteParameter_roundTripFloat(t *testing.T) {
+	tests := []struct {
+		testName  string
+		valueType dsModels.ValueType
+		pv        contract.PropertyValue
+	}{
+		{"Float32ScaleOffset", dsModels.Float32, contract.PropertyValue{Scale: "10", Offset: "-0.5"}},
+		{"Float64ScaleOffset", dsModels.Float64, contract.PropertyValue{Scale: "0.01", Offset: "-273.15"}},
+		{"Float64Base", dsModels.Float64, contract.PropertyValue{Base: "10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < roundTrips; i++ {
+				raw := r.Float64()*200 - 100
+				var cv *dsModels.CommandValue
+				if tt.valueType == dsModels.Float32 {
+					cv = newTransformValue(t, tt.valueType, float32(raw))
+				} else {
+					cv = newTransformValue(t, tt.valueType, raw)
+				}
+				raw = toFloat64(transformValue(t, cv))
+
+				if err := TransformReadResult(cv, tt.pv); err != nil {
+					t.Fatalf("Fail to transform read result of %v, error: %v", raw, err)
+				}
+				if err := TransformWriteParameter(cv, tt.pv); err != nil {
+					t.Fatalf("Fail to transform write parameter read from %v, error: %v", raw, err)
+				}
+				assert.InEpsilon(t, raw, toFloat64(transformValue(t, cv)), 1e-4)
+			}
+		})
+	}
+}
+
+func TestTransformWriteParameter_errors(t *testing.T) {
+	tests := []struct {
+		testName  string
+		valueType dsModels.ValueType
+		value     interface{}
+		pv        contract.PropertyValue
+		overflow  bool
+	}{
+		{"NegativeUnsigned", dsModels.Uint8, uint8(5), contract.PropertyValue{Offset: "10"}, true},
+		{"Int8OffsetOverflow", dsModels.Int8, int8(-100), contract.PropertyValue{Offset: "100"}, true},
+		{"Uint8ScaleOverflow", dsModels.Uint8, uint8(200), contract.PropertyValue{Scale: "0.5"}, true},
+		{"Int8ScaleOverflow", dsModels.Int8, int8(100), contract.PropertyValue{Scale: "0.1"}, true},
+		{"Int64ScaleOverflow", dsModels.Int64, int64(math.MaxInt64), contract.PropertyValue{Scale: "0.5"}, true},
+		{"Float32ScaleOverflow", dsModels.Float32, float32(math.MaxFloat32), contract.PropertyValue{Scale: "0.5"}, true},
+		{"BaseOfZero", dsModels.Uint8, uint8(0), contract.PropertyValue{Base: "10"}, true},
+		{"RightShiftOverflow", dsModels.Uint8, uint8(20), contract.PropertyValue{Shift: "-4"}, true},
+		{"LeftShiftNotExact", dsModels.Uint8, uint8(5), contract.PropertyValue{Shift: "2"}, false},
+		{"OutsideOfMask", dsModels.Uint16, uint16(300), contract.PropertyValue{Mask: "4080", Shift: "-4"}, false},
+		{"ZeroScale", dsModels.Int16, int16(3), contract.PropertyValue{Scale: "0"}, false},
+		{"InvalidOffset", dsModels.Int8, int8(3), contract.PropertyValue{Offset: "1.5"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cv := newTransformValue(t, tt.valueType, tt.value)
+			err := TransformWriteParameter(cv, tt.pv)
+			if !assert.Error(t, err) {
+				return
+			}
+			_, overflow := errors.Cause(err).(OverflowError)
+			assert.Equal(t, tt.overflow, overflow, err.Error())
+			assert.Equal(t, tt.value, transformValue(t, cv), "the value should not be changed on error")
+		})
+	}
+}
+
+// TestTransformWriteParameter_boundaries checks the writes whose results are
+// at the limits of the 64 bits integers, which float64 can't represent exactly.
+func TestTransformWriteParameter_boundaries(t *testing.T) {
+	tests := []struct {
+		testName  string
+		valueType dsModels.ValueType
+		value     interface{}
+		pv        contract.PropertyValue
+		expected  interface{}
+	}{
+		{"Int64ScaleToMax", dsModels.Int64, int64(1 << 62), contract.PropertyValue{Scale: "0.5"}, nil},
+		{"Int64NegativeScaleToMax", dsModels.Int64, int64(-1 << 62), contract.PropertyValue{Scale: "-0.5"}, nil},
+		{"Int64ScaleToMin", dsModels.Int64, int64(-1 << 62), contract.PropertyValue{Scale: "0.5"}, int64(math.MinInt64)},
+		{"Int64ScaleBelowMax", dsModels.Int64, int64(1<<62 - 512), contract.PropertyValue{Scale: "0.5"}, int64(1<<63 - 1024)},
+		{"Uint64ScaleToMax", dsModels.Uint64, uint64(1 << 63), contract.PropertyValue{Scale: "0.5"}, nil},
+		{"Uint64ScaleAboveMax", dsModels.Uint64, uint64(math.MaxUint64), contract.PropertyValue{Scale: "0.9999999"}, nil},
+		{"Uint64ScaleBelowMax", dsModels.Uint64, uint64(1<<63 - 1024), contract.PropertyValue{Scale: "0.5"}, uint64(1<<64 - 2048)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cv := newTransformValue(t, tt.valueType, tt.value)
+			err := TransformWriteParameter(cv, tt.pv)
+			if tt.expected == nil {
+				if assert.Error(t, err) {
+					_, overflow := errors.Cause(err).(OverflowError)
+					assert.True(t, overflow, err.Error())
+				}
+				assert.Equal(t, tt.value, transformValue(t, cv), "the value should not be changed on error")
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, transformValue(t, cv))
+			}
+		})
+	}
+}
+
+// TestTransformWriteParameter_roundTripBoundaries checks the round trips of
+// the raw values read at the limits of the 64 bits integers.
+func TestTransformWriteParameter_roundTripBoundaries(t *testing.T) {
+	tests := []struct {
+		testName  string
+		valueType dsModels.ValueType
+		raw       interface{}
+		pv        contract.PropertyValue
+	}{
+		{"Int64ScaleToMin", dsModels.Int64, int64(-1 << 62), contract.PropertyValue{Scale: "2"}},
+		{"Int64ScaleBelowMax", dsModels.Int64, int64(1<<62 - 512), contract.PropertyValue{Scale: "2"}},
+		{"Uint64ScaleBelowMax", dsModels.Uint64, uint64(1<<63 - 1024), contract.PropertyValue{Scale: "2"}},
+		{"Int64Base", dsModels.Int64, int64(62), contract.PropertyValue{Base: "2"}},
+		{"Uint64BaseToInt64Max", dsModels.Uint64, uint64(63), contract.PropertyValue{Base: "2"}},
+		{"Float64OffsetToInt64Min", dsModels.Float64, float64(0), contract.PropertyValue{Offset: "-9223372036854775808"}},
+		{"Float64OffsetToInt64Max", dsModels.Float64, float64(0), contract.PropertyValue{Offset: "9223372036854775808"}},
+		{"Float64OffsetToUint64Max", dsModels.Float64, float64(1 << 63), contract.PropertyValue{Offset: "9223372036854775808"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			cv := newTransformValue(t, tt.valueType, tt.raw)
+			if !assert.NoError(t, TransformReadResult(cv, tt.pv)) {
+				return
+			}
+			read := transformValue(t, cv)
+			if assert.NoError(t, TransformWriteParameter(cv, tt.pv), "write of %v", read) {
+				assert.Equal(t, tt.raw, transformValue(t, cv), "%v read from %v", read, tt.raw)
+			}
+		})
+	}
+}
+
+func TestMergeMaskedValue(t *testing.T) {
+	pv := contract.PropertyValue{Mask: "240", Shift: "-4"}
+	cv := newTransformValue(t, dsModels.Uint8, uint8(10))
+	current := newTransformValue(t, dsModels.Uint8, uint8(0x35))
+
+	assert.True(t, MaskedWrite(cv, pv))
+	if err := TransformWriteParameter(cv, pv); err != nil {
+		t.Fatalf("Fail to transform write parameter, error: %v", err)
+	}
+	if err := MergeMaskedValue(cv, current, pv); err != nil {
+		t.Fatalf("Fail to merge the masked value, error: %v", err)
+	}
+	assert.Equal(t, uint8(0xA5), transformValue(t, cv))
+
+	assert.False(t, MaskedWrite(newTransformValue(t, dsModels.Int8, int8(1)), pv))
+	assert.False(t, MaskedWrite(cv, contract.PropertyValue{Shift: "-4"}))
+	assert.Error(t, MergeMaskedValue(cv, newTransformValue(t, dsModels.Uint16, uint16(1)), pv))
+}
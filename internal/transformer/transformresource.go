@@ -0,0 +1,214 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"math"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+)
+
+// DeviceResource attributes of the transforms which aren't PropertyValue properties.
+const (
+	// TransformTableAttribute is a piecewise-linear calibration table of raw:value
+	// points, e.g. "0:-40, 512:25, 1023:125".
+	TransformTableAttribute = "transformTable"
+	// TransformExpressionAttribute is an arithmetic expression of the raw value,
+	// e.g. "(raw * 0.1) - 40".
+	TransformExpressionAttribute = "transformExpression"
+	// TransformInverseExpressionAttribute is the inverse of the transformExpression
+	// as an expression of the value, it is only required when the transformExpression
+	// isn't affine.
+	TransformInverseExpressionAttribute = "transformInverseExpression"
+)
+
+const (
+	expressionVariable        = "raw"
+	inverseExpressionVariable = "value"
+)
+
+// TransformReadResource applies the PropertyValue transforms of the DeviceResource
+// to the read value, followed by its transformTable and its transformExpression.
+func TransformReadResource(cv *dsModels.CommandValue, dr *contract.DeviceResource) error {
+	if err := TransformReadResult(cv, dr.Properties.Value); err != nil {
+		return err
+	}
+	return transformAttributes(cv, dr, readAttributes)
+}
+
+// TransformWriteResource applies the inverse of the transforms of the
+// DeviceResource to the written value, in the reverse order of TransformReadResource.
+func TransformWriteResource(cv *dsModels.CommandValue, dr *contract.DeviceResource) error {
+	if err := transformAttributes(cv, dr, writeAttributes); err != nil {
+		return err
+	}
+	return TransformWriteParameter(cv, dr.Properties.Value)
+}
+
+func readAttributes(x float64, attributes map[string]string) (float64, error) {
+	if source, ok := attributes[TransformTableAttribute]; ok {
+		t, err := parseCalibrationTable(source)
+		if err != nil {
+			return x, err
+		}
+		if x, err = t.read(x); err != nil {
+			return x, err
+		}
+	}
+	if source, ok := attributes[TransformExpressionAttribute]; ok {
+		e, err := parseExpression(source, expressionVariable)
+		if err != nil {
+			return x, err
+		}
+		return e.eval(x)
+	}
+	return x, nil
+}
+
+func writeAttributes(y float64, attributes map[string]string) (float64, error) {
+	if source, ok := attributes[TransformExpressionAttribute]; ok {
+		var err error
+		if y, err = inverseExpression(y, source, attributes[TransformInverseExpressionAttribute]); err != nil {
+			return y, err
+		}
+	}
+	if source, ok := attributes[TransformTableAttribute]; ok {
+		t, err := parseCalibrationTable(source)
+		if err != nil {
+			return y, err
+		}
+		return t.inverse(y)
+	}
+	return y, nil
+}
+
+// inverseExpression returns the raw value of the expression giving y, it
+// evaluates the inverse expression if any, otherwise it inverts the expression
+// if the expression is affine.
+func inverseExpression(y float64, source string, inverseSource string) (float64, error) {
+	if inverseSource != "" {
+		inverse, err := parseExpression(inverseSource, inverseExpressionVariable)
+		if err != nil {
+			return y, err
+		}
+		return inverse.eval(y)
+	}
+
+	e, err := parseExpression(source, expressionVariable)
+	if err != nil {
+		return y, err
+	}
+	a, b, ok := e.affine()
+	if !ok || a == 0 {
+		return y, fmt.Errorf("the expression %q cannot be inverted, the %s attribute is required to write the value",
+			source, TransformInverseExpressionAttribute)
+	}
+	return (y - b) / a, nil
+}
+
+// transformAttributes applies the transform to the numeric value, or to every
+// element of the numeric array, of cv. The result is rounded to the nearest
+// integer for the integer types, and must be in the range of the type of cv.
+func transformAttributes(cv *dsModels.CommandValue, dr *contract.DeviceResource, transform func(float64, map[string]string) (float64, error)) error {
+	_, hasTable := dr.Attributes[TransformTableAttribute]
+	_, hasExpression := dr.Attributes[TransformExpressionAttribute]
+	if !hasTable && !hasExpression {
+		return nil
+	}
+
+	var values []interface{}
+	var err error
+	if isNumericArray(cv.Type) {
+		values, err = arrayValuesForTransform(cv)
+	} else if _, ok, _ := NumericValue(cv); ok {
+		var value interface{}
+		value, err = commandValueForTransform(cv)
+		values = []interface{}{value}
+	} else {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, value := range values {
+		transformed, err := transform(toFloat64(value), dr.Attributes)
+		if err != nil {
+			return fmt.Errorf("transform failed for device resource '%v': %v", cv.DeviceResourceName, err)
+		}
+		if values[i], err = fromFloat64Checked(value, transformed); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Overflow failed for device resource '%v' ", cv.DeviceResourceName))
+		}
+	}
+
+	if isNumericArray(cv.Type) {
+		return replaceNewArrayCommandValue(cv, values)
+	}
+	return replaceNewCommandValue(cv, values[0])
+}
+
+func isInteger(value interface{}) bool {
+	switch value.(type) {
+	case float32, float64:
+		return false
+	}
+	return true
+}
+
+func toFloat64(value interface{}) float64 {
+	switch v := value.(type) {
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return math.NaN()
+}
+
+// fromFloat64 converts f to the type of value.
+func fromFloat64(value interface{}, f float64) interface{} {
+	switch value.(type) {
+	case uint8:
+		return uint8(f)
+	case uint16:
+		return uint16(f)
+	case uint32:
+		return uint32(f)
+	case uint64:
+		return uint64(f)
+	case int8:
+		return int8(f)
+	case int16:
+		return int16(f)
+	case int32:
+		return int32(f)
+	case int64:
+		return int64(f)
+	case float32:
+		return float32(f)
+	}
+	return f
+}
@@ -0,0 +1,112 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTransformResource(attributes map[string]string, pv contract.PropertyValue) *contract.DeviceResource {
+	return &contract.DeviceResource{Name: "test-object", Properties: contract.ProfileProperty{Value: pv}, Attributes: attributes}
+}
+
+func TestTransformResourceExpression(t *testing.T) {
+	dr := newTransformResource(map[string]string{TransformExpressionAttribute: "(raw * 0.1) - 40"}, contract.PropertyValue{})
+
+	cv, _ := dsModels.NewInt16Value(dr.Name, 0, 650)
+	if assert.NoError(t, TransformReadResource(cv, dr)) {
+		v, _ := cv.Int16Value()
+		assert.Equal(t, int16(25), v)
+	}
+
+	cv, _ = dsModels.NewInt16Value(dr.Name, 0, 25)
+	if assert.NoError(t, TransformWriteResource(cv, dr)) {
+		v, _ := cv.Int16Value()
+		assert.Equal(t, int16(650), v)
+	}
+
+	fv, _ := dsModels.NewFloat32Value(dr.Name, 0, 651)
+	if assert.NoError(t, TransformReadResource(fv, dr)) {
+		v, _ := fv.Float32Value()
+		assert.InDelta(t, 25.1, v, 1e-5)
+	}
+}
+
+func TestTransformResourceInverseExpression(t *testing.T) {
+	dr := newTransformResource(map[string]string{TransformExpressionAttribute: "raw ^ 2"}, contract.PropertyValue{})
+	cv, _ := dsModels.NewUint16Value(dr.Name, 0, 9)
+	assert.Error(t, TransformWriteResource(cv, dr), "a non-affine expression needs an inverse expression")
+
+	dr.Attributes[TransformInverseExpressionAttribute] = "sqrt(value)"
+	if assert.NoError(t, TransformWriteResource(cv, dr)) {
+		v, _ := cv.Uint16Value()
+		assert.Equal(t, uint16(3), v)
+	}
+}
+
+func TestTransformResourceOrder(t *testing.T) {
+	// a read applies the scale, then the table, then the expression
+	dr := newTransformResource(map[string]string{
+		TransformTableAttribute:      "0:-40, 512:25, 1023:125",
+		TransformExpressionAttribute: "raw * 10",
+	}, contract.PropertyValue{Scale: "2"})
+
+	cv, _ := dsModels.NewInt16Value(dr.Name, 0, 128)
+	if assert.NoError(t, TransformReadResource(cv, dr)) {
+		// 128 * 2 = 256 -> -7.5 -> -75
+		v, _ := cv.Int16Value()
+		assert.Equal(t, int16(-75), v)
+	}
+	if assert.NoError(t, TransformWriteResource(cv, dr)) {
+		v, _ := cv.Int16Value()
+		assert.Equal(t, int16(128), v)
+	}
+
+	cv, _ = dsModels.NewUint16Value(dr.Name, 0, 128)
+	assert.Error(t, TransformReadResource(cv, dr), "-75 overflows Uint16")
+}
+
+func TestTransformResourceTableRoundTrip(t *testing.T) {
+	dr := newTransformResource(map[string]string{TransformTableAttribute: "100:80, 200:40, 300:-10"}, contract.PropertyValue{})
+
+	cv, _ := dsModels.NewInt16ArrayValue(dr.Name, 0, []int16{100, 250, 300})
+	if assert.NoError(t, TransformReadResource(cv, dr)) {
+		v, _ := cv.Int16ArrayValue()
+		assert.Equal(t, []int16{80, 15, -10}, v)
+	}
+	if assert.NoError(t, TransformWriteResource(cv, dr)) {
+		v, _ := cv.Int16ArrayValue()
+		assert.Equal(t, []int16{100, 250, 300}, v)
+	}
+
+	cv, _ = dsModels.NewInt16Value(dr.Name, 0, 99)
+	assert.Error(t, TransformReadResource(cv, dr), "the raw value is outside the table")
+}
+
+func TestTransformResourceOverflow(t *testing.T) {
+	dr := newTransformResource(map[string]string{TransformExpressionAttribute: "raw * 100"}, contract.PropertyValue{})
+	cv, _ := dsModels.NewInt8Value(dr.Name, 0, 2)
+	err := TransformReadResource(cv, dr)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "overflow")
+	}
+
+	dr = newTransformResource(map[string]string{TransformExpressionAttribute: "ln(raw)"}, contract.PropertyValue{})
+	fv, _ := dsModels.NewFloat64Value(dr.Name, 0, -1)
+	assert.Error(t, TransformReadResource(fv, dr), "NaN should be reported as an overflow")
+}
+
+func TestTransformResourceIgnoresOtherTypes(t *testing.T) {
+	dr := newTransformResource(map[string]string{TransformExpressionAttribute: "raw * 2"}, contract.PropertyValue{})
+	cv := dsModels.NewStringValue(dr.Name, 0, "text")
+	assert.NoError(t, TransformReadResource(cv, dr))
+	assert.Equal(t, "text", cv.ValueToString())
+}
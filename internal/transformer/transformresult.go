@@ -8,17 +8,14 @@ package transformer
 
 import (
 	"bytes"
-	"context"
 	"encoding/binary"
 	"fmt"
 	"math"
 	"strconv"
 
-	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
@@ -31,8 +28,11 @@ const (
 )
 
 func TransformReadResult(cv *dsModels.CommandValue, pv contract.PropertyValue) error {
-	if cv.Type == dsModels.String || cv.Type == dsModels.Bool || cv.Type == dsModels.Binary {
-		return nil // do nothing for String, Bool and Binary
+	if cv.Type == dsModels.String || cv.Type == dsModels.Bool || cv.Type == dsModels.Binary ||
+		cv.Type == dsModels.BoolArray || cv.Type == dsModels.Object {
+		return nil // do nothing for String, Bool, Binary, BoolArray and Object
+	} else if isNumericArray(cv.Type) {
+		return transformReadArray(cv, pv)
 	}
 
 	value, err := commandValueForTransform(cv)
@@ -596,24 +596,12 @@ func replaceNewCommandValue(cv *dsModels.CommandValue, newValue interface{}) err
 	return err
 }
 
-func CheckAssertion(cv *dsModels.CommandValue, assertion string, device *contract.Device) error {
-	if assertion != "" && cv.ValueToString() != assertion {
-		device.OperatingState = contract.Disabled
-		cache.Devices().Update(*device)
-		ctx := context.WithValue(context.Background(), common.CorrelationHeader, uuid.New().String())
-		go common.DeviceClient.UpdateOpStateByName(device.Name, contract.Disabled, ctx)
-		msg := fmt.Sprintf("assertion (%s) failed with value: %s", assertion, cv.ValueToString())
-		common.LoggingClient.Error(msg)
-		return fmt.Errorf(msg)
-	}
-	return nil
-}
-
 func MapCommandValue(value *dsModels.CommandValue, mappings map[string]string) (*dsModels.CommandValue, bool) {
 	newValue, ok := mappings[value.ValueToString()]
 	var result *dsModels.CommandValue
 	if ok {
 		result = dsModels.NewStringValue(value.DeviceResourceName, value.Origin, newValue)
+		result.Quality = value.Quality
 	}
 	return result, ok
 }
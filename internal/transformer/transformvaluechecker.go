@@ -1,6 +1,6 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
-// Copyright (C) 2019 IOTech Ltd
+// Copyright (C) 2019-2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
@@ -9,8 +9,10 @@ package transformer
 import (
 	"fmt"
 	"math"
+	"math/big"
 
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 )
 
 func checkTransformedValueInRange(origin interface{}, transformed float64) bool {
@@ -64,3 +66,65 @@ func checkTransformedValueInRange(origin interface{}, transformed float64) bool
 
 	return inRange
 }
+
+// NumericValue returns the value of cv as an exact big.Float, ok is false
+// if cv isn't numeric.
+func NumericValue(cv *dsModels.CommandValue) (v *big.Float, ok bool, err error) {
+	v = new(big.Float).SetPrec(128)
+	switch cv.Type {
+	case dsModels.Uint8:
+		var n uint8
+		n, err = cv.Uint8Value()
+		v.SetUint64(uint64(n))
+	case dsModels.Uint16:
+		var n uint16
+		n, err = cv.Uint16Value()
+		v.SetUint64(uint64(n))
+	case dsModels.Uint32:
+		var n uint32
+		n, err = cv.Uint32Value()
+		v.SetUint64(uint64(n))
+	case dsModels.Uint64:
+		var n uint64
+		n, err = cv.Uint64Value()
+		v.SetUint64(n)
+	case dsModels.Int8:
+		var n int8
+		n, err = cv.Int8Value()
+		v.SetInt64(int64(n))
+	case dsModels.Int16:
+		var n int16
+		n, err = cv.Int16Value()
+		v.SetInt64(int64(n))
+	case dsModels.Int32:
+		var n int32
+		n, err = cv.Int32Value()
+		v.SetInt64(int64(n))
+	case dsModels.Int64:
+		var n int64
+		n, err = cv.Int64Value()
+		v.SetInt64(n)
+	case dsModels.Float32, dsModels.Float64:
+		var f float64
+		if cv.Type == dsModels.Float32 {
+			var f32 float32
+			f32, err = cv.Float32Value()
+			f = float64(f32)
+		} else {
+			f, err = cv.Float64Value()
+		}
+		if err == nil && math.IsNaN(f) {
+			err = fmt.Errorf("value of DeviceResource %s is not a number", cv.DeviceResourceName)
+		}
+		if err == nil {
+			v.SetFloat64(f)
+		}
+	default:
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
@@ -0,0 +1,65 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+// Policies of the String and Binary results longer than [Device] MaxCmdValueLen.
+const (
+	MaxCmdValueLenOff      = "off"
+	MaxCmdValueLenReject   = "reject"
+	MaxCmdValueLenTruncate = "truncate"
+)
+
+// CheckValueLength enforces the maxLen of the String and Binary CommandValue,
+// a maxLen lower than or equal to 0 means there is no limit. A value longer than
+// maxLen is truncated to maxLen bytes if the policy is "truncate", an error is
+// returned if it's "reject", and the value is left as it is otherwise, so the
+// results aren't limited unless a policy is configured. The String values are
+// truncated on a UTF-8 character boundary.
+func CheckValueLength(cv *dsModels.CommandValue, maxLen int, policy string) (*dsModels.CommandValue, error) {
+	policy = strings.ToLower(policy)
+	if maxLen <= 0 || (cv.Type != dsModels.String && cv.Type != dsModels.Binary) ||
+		(policy != MaxCmdValueLenReject && policy != MaxCmdValueLenTruncate) {
+		return cv, nil
+	}
+
+	length := len(cv.BinValue)
+	str, err := cv.StringValue()
+	if err == nil {
+		length = len(str)
+	}
+	if length <= maxLen {
+		return cv, nil
+	}
+
+	if policy == MaxCmdValueLenReject {
+		return cv, fmt.Errorf("the length %d of the %s value exceeds MaxCmdValueLen %d", length, cv.DeviceResourceName, maxLen)
+	}
+
+	common.LoggingClient.Warn(fmt.Sprintf("the length %d of the %s value exceeds MaxCmdValueLen %d, the value is truncated", length, cv.DeviceResourceName, maxLen))
+	if cv.Type == dsModels.Binary {
+		cv.BinValue = cv.BinValue[:maxLen]
+		return cv, nil
+	}
+
+	end := maxLen
+	for end > 0 && !utf8.RuneStart(str[end]) {
+		end--
+	}
+	result := dsModels.NewStringValue(cv.DeviceResourceName, cv.Origin, str[:end])
+	result.MediaType = cv.MediaType
+	result.Quality = cv.Quality
+	return result, nil
+}
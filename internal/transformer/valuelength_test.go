@@ -0,0 +1,60 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transformer
+
+import (
+	"testing"
+
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+func TestCheckValueLength(t *testing.T) {
+	binaryValue, _ := dsModels.NewBinaryValue("test-object", 0, []byte{0, 1, 2, 3, 4})
+	int32Value, _ := dsModels.NewInt32Value("test-object", 0, 123456)
+
+	tests := []struct {
+		name      string
+		cv        *dsModels.CommandValue
+		maxLen    int
+		policy    string
+		expected  string
+		expectErr bool
+	}{
+		{"NoLimit", dsModels.NewStringValue("test-object", 0, "abcdef"), 0, MaxCmdValueLenReject, "abcdef", false},
+		{"StringWithinLimit", dsModels.NewStringValue("test-object", 0, "abcdef"), 6, MaxCmdValueLenReject, "abcdef", false},
+		{"StringRejected", dsModels.NewStringValue("test-object", 0, "abcdef"), 5, MaxCmdValueLenReject, "", true},
+		{"DefaultPolicyOff", dsModels.NewStringValue("test-object", 0, "abcdef"), 5, "", "abcdef", false},
+		{"PolicyOff", dsModels.NewStringValue("test-object", 0, "abcdef"), 5, MaxCmdValueLenOff, "abcdef", false},
+		{"StringRejectedCaseInsensitive", dsModels.NewStringValue("test-object", 0, "abcdef"), 5, "Reject", "", true},
+		{"StringTruncated", dsModels.NewStringValue("test-object", 0, "abcdef"), 4, MaxCmdValueLenTruncate, "abcd", false},
+		{"StringTruncatedOnRuneBoundary", dsModels.NewStringValue("test-object", 0, "ab€cd"), 4, "Truncate", "ab", false},
+		{"BinaryRejected", binaryValue, 4, MaxCmdValueLenReject, "", true},
+		{"NumericNotChecked", int32Value, 1, MaxCmdValueLenReject, "123456", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cv, err := CheckValueLength(tt.cv, tt.maxLen, tt.policy)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("Expect an error for the value exceeding MaxCmdValueLen")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if str := cv.ValueToString(); str != tt.expected {
+				t.Fatalf("Unexpect test result, result '%v' should be '%v'", str, tt.expected)
+			}
+		})
+	}
+
+	cv, err := CheckValueLength(binaryValue, 3, MaxCmdValueLenTruncate)
+	if err != nil || len(cv.BinValue) != 3 {
+		t.Fatalf("Expect the binary value truncated to 3 bytes, got %v, %v", cv.BinValue, err)
+	}
+}
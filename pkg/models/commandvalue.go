@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -63,6 +64,42 @@ const (
 	// Binary indicates that the value is a binary payload that
 	// is stored in CommandValue's ByteArrRes member.
 	Binary
+	// BoolArray indicates that the value is a []bool that
+	// is stored in CommandValue's NumericValue member.
+	BoolArray
+	// Uint8Array indicates that the value is a []uint8 that
+	// is stored in CommandValue's NumericValue member.
+	Uint8Array
+	// Uint16Array indicates that the value is a []uint16 that
+	// is stored in CommandValue's NumericValue member.
+	Uint16Array
+	// Uint32Array indicates that the value is a []uint32 that
+	// is stored in CommandValue's NumericValue member.
+	Uint32Array
+	// Uint64Array indicates that the value is a []uint64 that
+	// is stored in CommandValue's NumericValue member.
+	Uint64Array
+	// Int8Array indicates that the value is a []int8 that
+	// is stored in CommandValue's NumericValue member.
+	Int8Array
+	// Int16Array indicates that the value is a []int16 that
+	// is stored in CommandValue's NumericValue member.
+	Int16Array
+	// Int32Array indicates that the value is a []int32 that
+	// is stored in CommandValue's NumericValue member.
+	Int32Array
+	// Int64Array indicates that the value is a []int64 that
+	// is stored in CommandValue's NumericValue member.
+	Int64Array
+	// Float32Array indicates that the value is a []float32 that
+	// is stored in CommandValue's NumericValue member.
+	Float32Array
+	// Float64Array indicates that the value is a []float64 that
+	// is stored in CommandValue's NumericValue member.
+	Float64Array
+	// Object indicates that the value is a map[string]interface{} that
+	// is stored in CommandValue's objectValue member.
+	Object
 )
 
 const (
@@ -104,6 +141,30 @@ func ParseValueType(typeName string) ValueType {
 		return Float64
 	case "BINARY":
 		return Binary
+	case "BOOLARRAY":
+		return BoolArray
+	case "UINT8ARRAY":
+		return Uint8Array
+	case "UINT16ARRAY":
+		return Uint16Array
+	case "UINT32ARRAY":
+		return Uint32Array
+	case "UINT64ARRAY":
+		return Uint64Array
+	case "INT8ARRAY":
+		return Int8Array
+	case "INT16ARRAY":
+		return Int16Array
+	case "INT32ARRAY":
+		return Int32Array
+	case "INT64ARRAY":
+		return Int64Array
+	case "FLOAT32ARRAY":
+		return Float32Array
+	case "FLOAT64ARRAY":
+		return Float64Array
+	case "OBJECT":
+		return Object
 	default:
 		return String
 	}
@@ -130,9 +191,18 @@ type CommandValue struct {
 	NumericValue []byte
 	// stringValue is a string value returned as a value by a ProtocolDriver instance.
 	stringValue string
+	// objectValue is a structured value returned as a value by a ProtocolDriver instance.
+	objectValue map[string]interface{}
 	// BinValue is a binary value with a maximum capacity of 16 MB,
 	// used to hold binary values returned by a ProtocolDriver instance.
 	BinValue []byte
+	// MediaType is the media type of the binary value, e.g. image/jpeg. If it's
+	// empty, the MediaType of the DeviceResource is used.
+	MediaType string
+	// Quality is the quality of the value, the zero value is good. The
+	// ProtocolDriver may set it, and the SDK degrades it when the transforms,
+	// the assertion or the mappings of the value fail.
+	Quality Quality
 }
 
 // NewBoolValue creates a CommandValue of Type Bool with the given value.
@@ -218,6 +288,93 @@ func NewFloat64Value(DeviceResourceName string, origin int64, value float64) (cv
 	return
 }
 
+// NewBoolArrayValue creates a CommandValue of Type BoolArray with the given value.
+func NewBoolArrayValue(DeviceResourceName string, origin int64, value []bool) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: BoolArray}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewUint8ArrayValue creates a CommandValue of Type Uint8Array with the given value.
+func NewUint8ArrayValue(DeviceResourceName string, origin int64, value []uint8) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Uint8Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewUint16ArrayValue creates a CommandValue of Type Uint16Array with the given value.
+func NewUint16ArrayValue(DeviceResourceName string, origin int64, value []uint16) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Uint16Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewUint32ArrayValue creates a CommandValue of Type Uint32Array with the given value.
+func NewUint32ArrayValue(DeviceResourceName string, origin int64, value []uint32) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Uint32Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewUint64ArrayValue creates a CommandValue of Type Uint64Array with the given value.
+func NewUint64ArrayValue(DeviceResourceName string, origin int64, value []uint64) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Uint64Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewInt8ArrayValue creates a CommandValue of Type Int8Array with the given value.
+func NewInt8ArrayValue(DeviceResourceName string, origin int64, value []int8) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Int8Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewInt16ArrayValue creates a CommandValue of Type Int16Array with the given value.
+func NewInt16ArrayValue(DeviceResourceName string, origin int64, value []int16) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Int16Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewInt32ArrayValue creates a CommandValue of Type Int32Array with the given value.
+func NewInt32ArrayValue(DeviceResourceName string, origin int64, value []int32) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Int32Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewInt64ArrayValue creates a CommandValue of Type Int64Array with the given value.
+func NewInt64ArrayValue(DeviceResourceName string, origin int64, value []int64) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Int64Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewFloat32ArrayValue creates a CommandValue of Type Float32Array with the given value.
+func NewFloat32ArrayValue(DeviceResourceName string, origin int64, value []float32) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Float32Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewFloat64ArrayValue creates a CommandValue of Type Float64Array with the given value.
+func NewFloat64ArrayValue(DeviceResourceName string, origin int64, value []float64) (cv *CommandValue, err error) {
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Float64Array}
+	err = encodeValue(cv, value)
+	return
+}
+
+// NewObjectValue creates a CommandValue of Type Object with the given value,
+// and returns error if the value cannot be encoded in JSON.
+func NewObjectValue(DeviceResourceName string, origin int64, value map[string]interface{}) (cv *CommandValue, err error) {
+	if _, err = json.Marshal(value); err != nil {
+		return nil, fmt.Errorf("the Object value cannot be encoded in JSON: %v", err)
+	}
+	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: Object, objectValue: value}
+	return
+}
+
 //NewCommandValue create a CommandValue according to the Type supplied.
 func NewCommandValue(DeviceResourceName string, origin int64, value interface{}, t ValueType) (cv *CommandValue, err error) {
 	cv = &CommandValue{DeviceResourceName: DeviceResourceName, Origin: origin, Type: t}
@@ -227,6 +384,8 @@ func NewCommandValue(DeviceResourceName string, origin int64, value interface{},
 		cv.BinValue = value.([]byte)
 	case String:
 		cv.stringValue = value.(string)
+	case Object:
+		cv.objectValue = value.(map[string]interface{})
 	default:
 		err = encodeValue(cv, value)
 	}
@@ -261,6 +420,14 @@ func (cv *CommandValue) ValueToString(encoding ...string) (str string) {
 	if cv.Type == String {
 		str = cv.stringValue
 		return
+	} else if cv.Type == Object {
+		res, err := json.Marshal(cv.objectValue)
+		if err != nil {
+			str = err.Error()
+		} else {
+			str = string(res)
+		}
+		return
 	}
 
 	reader := bytes.NewReader(cv.NumericValue)
@@ -349,6 +516,8 @@ func (cv *CommandValue) ValueToString(encoding ...string) (str string) {
 		} else if floatEncoding == contract.Base64Encoding {
 			str = base64.StdEncoding.EncodeToString(cv.NumericValue)
 		}
+	case BoolArray, Uint8Array, Uint16Array, Uint32Array, Uint64Array, Int8Array, Int16Array, Int32Array, Int64Array, Float32Array, Float64Array:
+		str = cv.arrayValueToString()
 	case Binary:
 		// produce string representation of first 20 bytes of binary value
 		str = fmt.Sprintf(fmt.Sprintf("Binary: [%v...]", string(cv.BinValue[:20])))
@@ -357,6 +526,52 @@ func (cv *CommandValue) ValueToString(encoding ...string) (str string) {
 	return
 }
 
+// arrayValueToString returns the array value as a JSON array.
+func (cv *CommandValue) arrayValueToString() string {
+	var value interface{}
+	var err error
+	switch cv.Type {
+	case BoolArray:
+		value, err = cv.BoolArrayValue()
+	case Uint8Array:
+		// encoding/json marshals []uint8 to a base64 string rather than an array
+		var res []uint8
+		res, err = cv.Uint8ArrayValue()
+		elements := make([]uint16, len(res))
+		for i, e := range res {
+			elements[i] = uint16(e)
+		}
+		value = elements
+	case Uint16Array:
+		value, err = cv.Uint16ArrayValue()
+	case Uint32Array:
+		value, err = cv.Uint32ArrayValue()
+	case Uint64Array:
+		value, err = cv.Uint64ArrayValue()
+	case Int8Array:
+		value, err = cv.Int8ArrayValue()
+	case Int16Array:
+		value, err = cv.Int16ArrayValue()
+	case Int32Array:
+		value, err = cv.Int32ArrayValue()
+	case Int64Array:
+		value, err = cv.Int64ArrayValue()
+	case Float32Array:
+		value, err = cv.Float32ArrayValue()
+	case Float64Array:
+		value, err = cv.Float64ArrayValue()
+	}
+	if err != nil {
+		return err.Error()
+	}
+
+	str, err := json.Marshal(value)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
 func getFloatEncoding(encoding []string) string {
 	if len(encoding) > 0 {
 		if encoding[0] == contract.Base64Encoding {
@@ -403,6 +618,30 @@ func (cv *CommandValue) String() (str string) {
 		typeStr = "Float64: "
 	case Binary:
 		typeStr = "Binary: "
+	case BoolArray:
+		typeStr = "BoolArray: "
+	case Uint8Array:
+		typeStr = "Uint8Array: "
+	case Uint16Array:
+		typeStr = "Uint16Array: "
+	case Uint32Array:
+		typeStr = "Uint32Array: "
+	case Uint64Array:
+		typeStr = "Uint64Array: "
+	case Int8Array:
+		typeStr = "Int8Array: "
+	case Int16Array:
+		typeStr = "Int16Array: "
+	case Int32Array:
+		typeStr = "Int32Array: "
+	case Int64Array:
+		typeStr = "Int64Array: "
+	case Float32Array:
+		typeStr = "Float32Array: "
+	case Float64Array:
+		typeStr = "Float64Array: "
+	case Object:
+		typeStr = "Object: "
 	}
 
 	valueStr := typeStr + cv.ValueToString()
@@ -431,6 +670,15 @@ func (cv *CommandValue) StringValue() (string, error) {
 	return value, nil
 }
 
+// ObjectValue returns the value in map[string]interface{} data type, and returns error if the Type is not Object.
+func (cv *CommandValue) ObjectValue() (map[string]interface{}, error) {
+	value := cv.objectValue
+	if cv.Type != Object {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	return value, nil
+}
+
 // Uint8Value returns the value in uint8 data type, and returns error if the Type is not Uint8.
 func (cv *CommandValue) Uint8Value() (uint8, error) {
 	var value uint8
@@ -539,3 +787,124 @@ func (cv *CommandValue) BinaryValue() ([]byte, error) {
 	}
 	return cv.BinValue, nil
 }
+
+// BoolArrayValue returns the value in []bool data type, and returns error if the Type is not BoolArray.
+func (cv *CommandValue) BoolArrayValue() ([]bool, error) {
+	var value []bool
+	if cv.Type != BoolArray {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]bool, len(cv.NumericValue))
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Uint8ArrayValue returns the value in []uint8 data type, and returns error if the Type is not Uint8Array.
+func (cv *CommandValue) Uint8ArrayValue() ([]uint8, error) {
+	var value []uint8
+	if cv.Type != Uint8Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]uint8, len(cv.NumericValue))
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Uint16ArrayValue returns the value in []uint16 data type, and returns error if the Type is not Uint16Array.
+func (cv *CommandValue) Uint16ArrayValue() ([]uint16, error) {
+	var value []uint16
+	if cv.Type != Uint16Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]uint16, len(cv.NumericValue)/2)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Uint32ArrayValue returns the value in []uint32 data type, and returns error if the Type is not Uint32Array.
+func (cv *CommandValue) Uint32ArrayValue() ([]uint32, error) {
+	var value []uint32
+	if cv.Type != Uint32Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]uint32, len(cv.NumericValue)/4)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Uint64ArrayValue returns the value in []uint64 data type, and returns error if the Type is not Uint64Array.
+func (cv *CommandValue) Uint64ArrayValue() ([]uint64, error) {
+	var value []uint64
+	if cv.Type != Uint64Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]uint64, len(cv.NumericValue)/8)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Int8ArrayValue returns the value in []int8 data type, and returns error if the Type is not Int8Array.
+func (cv *CommandValue) Int8ArrayValue() ([]int8, error) {
+	var value []int8
+	if cv.Type != Int8Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]int8, len(cv.NumericValue))
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Int16ArrayValue returns the value in []int16 data type, and returns error if the Type is not Int16Array.
+func (cv *CommandValue) Int16ArrayValue() ([]int16, error) {
+	var value []int16
+	if cv.Type != Int16Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]int16, len(cv.NumericValue)/2)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Int32ArrayValue returns the value in []int32 data type, and returns error if the Type is not Int32Array.
+func (cv *CommandValue) Int32ArrayValue() ([]int32, error) {
+	var value []int32
+	if cv.Type != Int32Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]int32, len(cv.NumericValue)/4)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Int64ArrayValue returns the value in []int64 data type, and returns error if the Type is not Int64Array.
+func (cv *CommandValue) Int64ArrayValue() ([]int64, error) {
+	var value []int64
+	if cv.Type != Int64Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]int64, len(cv.NumericValue)/8)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Float32ArrayValue returns the value in []float32 data type, and returns error if the Type is not Float32Array.
+func (cv *CommandValue) Float32ArrayValue() ([]float32, error) {
+	var value []float32
+	if cv.Type != Float32Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]float32, len(cv.NumericValue)/4)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
+
+// Float64ArrayValue returns the value in []float64 data type, and returns error if the Type is not Float64Array.
+func (cv *CommandValue) Float64ArrayValue() ([]float64, error) {
+	var value []float64
+	if cv.Type != Float64Array {
+		return value, fmt.Errorf("the data type is not %T", value)
+	}
+	value = make([]float64, len(cv.NumericValue)/8)
+	err := decodeValue(bytes.NewReader(cv.NumericValue), value)
+	return value, err
+}
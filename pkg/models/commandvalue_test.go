@@ -829,3 +829,87 @@ func TestNewBinaryValueExceedsConstraints(t *testing.T) {
 		// PASS
 	}
 }
+
+func TestNewArrayValues(t *testing.T) {
+	boolArray, _ := NewBoolArrayValue("resource", 0, []bool{true, false})
+	uint8Array, _ := NewUint8ArrayValue("resource", 0, []uint8{0, 255})
+	uint64Array, _ := NewUint64ArrayValue("resource", 0, []uint64{math.MaxUint64})
+	int16Array, _ := NewInt16ArrayValue("resource", 0, []int16{-1, 2, 3})
+	int32Array, _ := NewInt32ArrayValue("resource", 0, []int32{})
+	float32Array, _ := NewFloat32ArrayValue("resource", 0, []float32{1.5, -2.25})
+	float64Array, _ := NewFloat64ArrayValue("resource", 0, []float64{0.1})
+
+	tests := []struct {
+		name      string
+		cv        *CommandValue
+		valueType ValueType
+		expected  string
+	}{
+		{"BoolArray", boolArray, BoolArray, "[true,false]"},
+		{"Uint8Array", uint8Array, Uint8Array, "[0,255]"},
+		{"Uint64Array", uint64Array, Uint64Array, "[18446744073709551615]"},
+		{"Int16Array", int16Array, Int16Array, "[-1,2,3]"},
+		{"EmptyInt32Array", int32Array, Int32Array, "[]"},
+		{"Float32Array", float32Array, Float32Array, "[1.5,-2.25]"},
+		{"Float64Array", float64Array, Float64Array, "[0.1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cv.Type != tt.valueType {
+				t.Errorf("expected type %v, got %v", tt.valueType, tt.cv.Type)
+			}
+			if str := tt.cv.ValueToString(); str != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, str)
+			}
+		})
+	}
+
+	cv, err := NewCommandValue("resource", 0, []int16{-1, 2, 3}, Int16Array)
+	if err != nil || !reflect.DeepEqual(cv, int16Array) {
+		t.Errorf("CommandValue returned from NewCommandValue doesn't match NewInt16ArrayValue")
+	}
+	int16s, err := int16Array.Int16ArrayValue()
+	if err != nil || !reflect.DeepEqual(int16s, []int16{-1, 2, 3}) {
+		t.Errorf("unexpected Int16ArrayValue %v, %v", int16s, err)
+	}
+	float32s, err := float32Array.Float32ArrayValue()
+	if err != nil || !reflect.DeepEqual(float32s, []float32{1.5, -2.25}) {
+		t.Errorf("unexpected Float32ArrayValue %v, %v", float32s, err)
+	}
+	if _, err = int16Array.Int32ArrayValue(); err == nil {
+		t.Errorf("expected an error reading a Int16Array as Int32Array")
+	}
+	if ParseValueType("Float64Array") != Float64Array || ParseValueType("uint8array") != Uint8Array {
+		t.Errorf("array ValueTypes are not parsed")
+	}
+}
+
+func TestNewObjectValue(t *testing.T) {
+	value := map[string]interface{}{"presentValue": 21.5, "statusFlags": []bool{false, false}, "units": "degrees-celsius"}
+	cv, err := NewObjectValue("resource", 0, value)
+	if err != nil {
+		t.Fatalf("Error creating command value: %v", err)
+	}
+	if cv.Type != Object {
+		t.Errorf("expected type Object, got %v", cv.Type)
+	}
+	expected := `{"presentValue":21.5,"statusFlags":[false,false],"units":"degrees-celsius"}`
+	if str := cv.ValueToString(); str != expected {
+		t.Errorf("expected %s, got %s", expected, str)
+	}
+	if v, err := cv.ObjectValue(); err != nil || !reflect.DeepEqual(v, value) {
+		t.Errorf("unexpected ObjectValue %v, %v", v, err)
+	}
+	if _, err = cv.StringValue(); err == nil {
+		t.Errorf("expected an error reading an Object as String")
+	}
+
+	test, err := NewCommandValue("resource", 0, value, Object)
+	if err != nil || !reflect.DeepEqual(cv, test) {
+		t.Errorf("CommandValue returned from NewCommandValue doesn't match NewObjectValue")
+	}
+
+	if _, err = NewObjectValue("resource", 0, map[string]interface{}{"channel": make(chan int)}); err == nil {
+		t.Errorf("expected an error creating an Object which cannot be encoded in JSON")
+	}
+}
@@ -7,13 +7,36 @@
 package models
 
 import (
+	"encoding/json"
+
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/ugorji/go/codec"
 )
 
 // Event is a wrapper of contract.Event to provide more Binary related operation in Device Service.
 type Event struct {
 	contract.Event
 	EncodedEvent []byte
+	// MediaTypes are the media types of the binary readings by reading name.
+	MediaTypes map[string]string
+	// Qualities are the qualities of the readings at the same index, the
+	// readings without quality are good.
+	Qualities []Quality
+}
+
+// detailedReading is a contract.Reading along with its quality and the media
+// type of its binary value.
+type detailedReading struct {
+	contract.Reading
+	Quality   *Quality `json:"quality,omitempty" codec:"quality,omitempty"`
+	MediaType string   `json:"mediaType,omitempty" codec:"mediaType,omitempty"`
+}
+
+// detailedEvent is a contract.Event whose readings carry their quality and
+// media type.
+type detailedEvent struct {
+	contract.Event
+	Readings []detailedReading `json:"readings,omitempty" codec:"readings,omitempty"`
 }
 
 // HasBinaryValue confirms whether an event contains one or more
@@ -28,3 +51,59 @@ func (e Event) HasBinaryValue() bool {
 	}
 	return false
 }
+
+// SingleBinaryReading returns the reading and its media type if the event
+// contains a single reading, populated with a BinaryValue payload.
+func (e Event) SingleBinaryReading() (reading contract.Reading, mediaType string, ok bool) {
+	if len(e.Readings) != 1 || len(e.Readings[0].BinaryValue) == 0 {
+		return reading, "", false
+	}
+	reading = e.Readings[0]
+	return reading, e.MediaTypes[reading.Name], true
+}
+
+// HasDegradedReadings returns whether the quality of one or more readings
+// isn't good.
+func (e Event) HasDegradedReadings() bool {
+	for _, q := range e.Qualities {
+		if !q.IsGood() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasReadingDetails returns whether the quality of one or more readings isn't
+// good or one or more binary readings have a media type, which the EventClient
+// MarshalEvent leaves out.
+func (e Event) HasReadingDetails() bool {
+	return e.HasDegradedReadings() || len(e.MediaTypes) > 0
+}
+
+// EncodeWithDetails encodes the event like the EventClient MarshalEvent, as
+// CBOR if it contains binary readings and as JSON otherwise, adding a quality
+// field to the readings which aren't good and a mediaType field to the binary
+// readings which have a media type.
+func (e Event) EncodeWithDetails() ([]byte, error) {
+	qe := detailedEvent{Event: e.Event, Readings: make([]detailedReading, len(e.Readings))}
+	for i, r := range e.Readings {
+		qe.Readings[i].Reading = r
+		if i < len(e.Qualities) && !e.Qualities[i].IsGood() {
+			q := e.Qualities[i]
+			qe.Readings[i].Quality = &q
+		}
+		if len(r.BinaryValue) > 0 {
+			qe.Readings[i].MediaType = e.MediaTypes[r.Name]
+		}
+	}
+
+	if !e.HasBinaryValue() {
+		return json.Marshal(qe)
+	}
+	var handle codec.CborHandle
+	data := make([]byte, 0, 64)
+	if err := codec.NewEncoderBytes(&data, &handle).Encode(qe); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
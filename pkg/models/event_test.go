@@ -0,0 +1,121 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
+)
+
+func TestSingleBinaryReading(t *testing.T) {
+	image := contract.Reading{Name: "Image", BinaryValue: []byte{0x89, 'P', 'N', 'G'}}
+	rotation := contract.Reading{Name: "Xrotation", Value: "1"}
+	mediaTypes := map[string]string{"Image": "image/png"}
+
+	tests := []struct {
+		name      string
+		readings  []contract.Reading
+		mediaType string
+		ok        bool
+	}{
+		{"SingleBinaryReading", []contract.Reading{image}, "image/png", true},
+		{"NotBinaryReading", []contract.Reading{rotation}, "", false},
+		{"SeveralReadings", []contract.Reading{image, rotation}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Event{Event: contract.Event{Readings: tt.readings}, MediaTypes: mediaTypes}
+			reading, mediaType, ok := e.SingleBinaryReading()
+			if ok != tt.ok || mediaType != tt.mediaType {
+				t.Errorf("expected %s, %v, got %s, %v", tt.mediaType, tt.ok, mediaType, ok)
+			}
+			if ok && reading.Name != "Image" {
+				t.Errorf("unexpected reading %v", reading)
+			}
+		})
+	}
+}
+
+func TestEncodeWithDetails(t *testing.T) {
+	rotation := contract.Reading{Name: "Xrotation", Value: "1"}
+	temperature := contract.Reading{Name: "Temperature", Value: "-300"}
+	image := contract.Reading{Name: "Image", BinaryValue: []byte{0x89, 'P', 'N', 'G'}}
+	bad := Quality{Status: QualityBad, Reason: ReasonTransformFailed}
+
+	tests := []struct {
+		name              string
+		readings          []contract.Reading
+		decode            func(data []byte, v interface{}) error
+		expectedMediaType string
+	}{
+		{"JSON", []contract.Reading{rotation, temperature}, json.Unmarshal, ""},
+		{"CBOR", []contract.Reading{image, temperature}, func(data []byte, v interface{}) error {
+			return codec.NewDecoderBytes(data, &codec.CborHandle{}).Decode(v)
+		}, "image/png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Event{
+				Event:      contract.Event{Device: "device", Readings: tt.readings},
+				MediaTypes: map[string]string{"Image": "image/png"},
+				Qualities:  []Quality{{}, bad},
+			}
+			assert.True(t, e.HasDegradedReadings())
+
+			data, err := e.EncodeWithDetails()
+			if !assert.NoError(t, err) {
+				return
+			}
+			var decoded struct {
+				Device   string `json:"device" codec:"device"`
+				Readings []struct {
+					Name      string   `json:"name" codec:"name"`
+					Quality   *Quality `json:"quality" codec:"quality"`
+					MediaType string   `json:"mediaType" codec:"mediaType"`
+				} `json:"readings" codec:"readings"`
+			}
+			if !assert.NoError(t, tt.decode(data, &decoded)) {
+				return
+			}
+			assert.Equal(t, "device", decoded.Device)
+			if assert.Len(t, decoded.Readings, 2) {
+				assert.Equal(t, tt.readings[0].Name, decoded.Readings[0].Name)
+				assert.Nil(t, decoded.Readings[0].Quality)
+				assert.Equal(t, tt.expectedMediaType, decoded.Readings[0].MediaType)
+				assert.Equal(t, "Temperature", decoded.Readings[1].Name)
+				assert.Equal(t, &bad, decoded.Readings[1].Quality)
+			}
+		})
+	}
+}
+
+func TestHasReadingDetails(t *testing.T) {
+	image := contract.Reading{Name: "Image", BinaryValue: []byte{0x89, 'P', 'N', 'G'}}
+	e := Event{Event: contract.Event{Readings: []contract.Reading{image}}}
+	assert.False(t, e.HasReadingDetails())
+
+	// the media type of a binary reading is only carried by the detailed encoding
+	e.MediaTypes = map[string]string{"Image": "image/png"}
+	assert.True(t, e.HasReadingDetails())
+	assert.False(t, e.HasDegradedReadings())
+}
+
+func TestDegradeQuality(t *testing.T) {
+	cv := NewStringValue("resource", 0, "value")
+	assert.False(t, Event{Qualities: []Quality{cv.Quality}}.HasDegradedReadings())
+
+	cv.DegradeQuality(QualityUncertain, ReasonMappingFailed)
+	assert.Equal(t, Quality{Status: QualityUncertain, Reason: ReasonMappingFailed}, cv.Quality)
+	cv.DegradeQuality(QualityBad, ReasonAssertionFailed)
+	cv.DegradeQuality(QualityUncertain, ReasonMappingFailed)
+	cv.DegradeQuality(QualityBad, ReasonTransformFailed)
+	assert.Equal(t, Quality{Status: QualityBad, Reason: ReasonAssertionFailed}, cv.Quality)
+}
@@ -1,20 +1,38 @@
 // -*- Mode: Go; indent-tabs-mode: t -*-
 //
 // Copyright (C) 2018 Canonical Ltd
+// Copyright (C) 2020 IOTech Ltd
 //
 // SPDX-License-Identifier: Apache-2.0
 
 package models
 
+import (
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+)
+
 // ProtocolDiscovery is a low-level device-specific interface implemented
-// by device services that support dynamic device discovery.
+// by device services that support dynamic device discovery. A ProtocolDriver
+// which also implements this interface is registered as the discoverer of
+// the Device Service automatically.
 type ProtocolDiscovery interface {
 	// Discover triggers protocol specific device discovery, which is
 	// a synchronous operation which returns a list of new devices
-	// which may be added to the device service based on service
-	// config. This function may also optionally trigger sensor
-	// discovery, which could result in dynamic device profile creation.
-	//
-	// TODO: add models.ScanList (or define locally) for devices
-	Discover() (devices *interface{}, err error)
+	// which may be added to the device service based on the
+	// ProvisionWatchers of the device service.
+	Discover() ([]DiscoveredDevice, error)
+}
+
+// DiscoveredDevice defines the required information for a found device.
+type DiscoveredDevice struct {
+	// Name is the name of the found device, it's used as the Device name
+	// when the device is created in Core Metadata
+	Name string
+	// Protocols is the protocol properties of the found device, which are
+	// matched against the Identifiers of the ProvisionWatchers
+	Protocols map[string]contract.ProtocolProperties
+	// Description is the description of the found device
+	Description string
+	// Labels are the labels applied to the found device
+	Labels []string
 }
@@ -13,6 +13,8 @@
 package models
 
 import (
+	"context"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
 )
@@ -54,3 +56,23 @@ type ProtocolDriver interface {
 	// when a Device associated with this Device Service is removed
 	RemoveDevice(deviceName string, protocols map[string]contract.ProtocolProperties) error
 }
+
+// ContextProtocolDriver is an optional extension of the ProtocolDriver interface.
+// If the ProtocolDriver also implements this interface, the SDK calls these
+// functions instead of HandleReadCommands and HandleWriteCommands. The given
+// context carries the correlation ID of the request, which can be retrieved with
+// ctx.Value(clients.CorrelationHeader), and a deadline derived from the
+// Service.Timeout configuration. The context is cancelled when the deadline
+// expires or the REST client disconnects, so the driver should abort any pending
+// I/O and return as soon as ctx.Done() is closed.
+type ContextProtocolDriver interface {
+	// HandleReadCommandsWithContext passes a slice of CommandRequest struct each
+	// representing a ResourceOperation for a specific device resource.
+	HandleReadCommandsWithContext(ctx context.Context, deviceName string, protocols map[string]contract.ProtocolProperties, reqs []CommandRequest) ([]*CommandValue, error)
+
+	// HandleWriteCommandsWithContext passes a slice of CommandRequest struct each
+	// representing a ResourceOperation for a specific device resource.
+	// Since the commands are actuation commands, params provide parameters for the
+	// individual command.
+	HandleWriteCommandsWithContext(ctx context.Context, deviceName string, protocols map[string]contract.ProtocolProperties, reqs []CommandRequest, params []*CommandValue) error
+}
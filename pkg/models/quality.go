@@ -0,0 +1,63 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// QualityStatus indicates whether a CommandValue can be trusted.
+type QualityStatus string
+
+const (
+	// QualityGood indicates that the value can be trusted.
+	QualityGood QualityStatus = "good"
+	// QualityUncertain indicates that the value may not be accurate, e.g. it
+	// failed a warn assertion or no mapping matched it.
+	QualityUncertain QualityStatus = "uncertain"
+	// QualityBad indicates that the value shouldn't be used, e.g. it couldn't
+	// be transformed or it failed a disable assertion.
+	QualityBad QualityStatus = "bad"
+)
+
+// Reason codes of the qualities set by the SDK, a ProtocolDriver may use its own.
+const (
+	ReasonTransformFailed = "TransformFailed"
+	ReasonAssertionFailed = "AssertionFailed"
+	ReasonMappingFailed   = "MappingFailed"
+)
+
+// Quality is the quality of a CommandValue, it's carried into the reading of
+// the value unless it's good.
+type Quality struct {
+	Status QualityStatus `json:"status,omitempty" codec:"status,omitempty"`
+	// Reason is a code which explains a status other than good.
+	Reason string `json:"reason,omitempty" codec:"reason,omitempty"`
+}
+
+// IsGood returns whether the status is good, an empty status is good.
+func (q Quality) IsGood() bool {
+	return q.Status == "" || q.Status == QualityGood
+}
+
+// rank orders the statuses from the best to the worst.
+func (q Quality) rank() int {
+	switch q.Status {
+	case QualityUncertain:
+		return 1
+	case QualityBad:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// DegradeQuality sets the quality of the CommandValue to the status and the
+// reason, unless its current status is already as bad, so that the first
+// reason of the worst status is kept.
+func (cv *CommandValue) DegradeQuality(status QualityStatus, reason string) {
+	q := Quality{Status: status, Reason: reason}
+	if q.rank() > cv.Quality.rank() {
+		cv.Quality = q
+	}
+}
@@ -19,13 +19,17 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/edgexfoundry/device-sdk-go/internal/async"
+	"github.com/edgexfoundry/device-sdk-go/internal/autodiscovery"
 	"github.com/edgexfoundry/device-sdk-go/internal/autoevent"
 	"github.com/edgexfoundry/device-sdk-go/internal/cache"
 	"github.com/edgexfoundry/device-sdk-go/internal/clients"
 	"github.com/edgexfoundry/device-sdk-go/internal/common"
 	configLoader "github.com/edgexfoundry/device-sdk-go/internal/config"
 	"github.com/edgexfoundry/device-sdk-go/internal/controller"
+	"github.com/edgexfoundry/device-sdk-go/internal/handler"
 	"github.com/edgexfoundry/device-sdk-go/internal/provision"
+	"github.com/edgexfoundry/device-sdk-go/internal/publisher"
 	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/types"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
@@ -39,11 +43,10 @@ var (
 // A Service listens for requests and routes them to the right command
 type Service struct {
 	svcInfo      *common.ServiceInfo
-	discovery    dsModels.ProtocolDiscovery
 	initAttempts int
 	initialized  bool
-	stopped      bool
 	asyncCh      chan *dsModels.AsyncValues
+	asyncPool    *async.Pool
 	startTime    time.Time
 	controller   controller.RestController
 }
@@ -58,8 +61,10 @@ func (s *Service) Version() string {
 	return common.ServiceVersion
 }
 
+// Discovery returns the ProtocolDiscovery registered by the Driver, or nil if
+// the Driver doesn't support dynamic device discovery.
 func (s *Service) Discovery() dsModels.ProtocolDiscovery {
-	return s.discovery
+	return common.Discovery
 }
 
 // AsyncReadings returns a bool value to indicate whether the asynchronous reading is enabled.
@@ -87,6 +92,13 @@ func (s *Service) Start(errChan chan error) (err error) {
 
 	// initialize devices, deviceResources & profiles
 	cache.InitCache()
+	// the devices added later run their InitCmd when Core Metadata calls back
+	existingDevices := cache.Devices().All()
+
+	common.Publisher, err = publisher.NewEventPublisher(common.CurrentConfig.EventPublisher)
+	if err != nil {
+		return fmt.Errorf("Failed to create the EventPublisher: %v", err)
+	}
 
 	// Setup REST API.
 	// Must occur before initialize driver in case driver needs to add route(s)
@@ -101,7 +113,8 @@ func (s *Service) Start(errChan chan error) (err error) {
 	// initialize driver
 	if common.CurrentConfig.Service.EnableAsyncReadings {
 		s.asyncCh = make(chan *dsModels.AsyncValues, common.CurrentConfig.Service.AsyncBufferSize)
-		go processAsyncResults()
+		s.asyncPool = async.NewPool(s.asyncCh, common.CurrentConfig.Service.AsyncWorkers, common.CurrentConfig.Service.AsyncBufferSize, processAsyncResults)
+		s.asyncPool.Start()
 	}
 	err = common.Driver.Initialize(common.LoggingClient, s.asyncCh)
 	if err != nil {
@@ -118,8 +131,11 @@ func (s *Service) Start(errChan chan error) (err error) {
 		return fmt.Errorf("Failed to create the pre-defined Devices")
 	}
 
+	for _, d := range existingDevices {
+		handler.StartInitCmd(d)
+	}
 	autoevent.GetManager().StartAutoEvents()
-	http.TimeoutHandler(nil, time.Millisecond*time.Duration(s.svcInfo.Timeout), "Request timed out")
+	autodiscovery.Run()
 
 	common.LoggingClient.Info("Service started in: " + time.Since(s.startTime).String())
 	common.LoggingClient.Debug("*Service Start() exit")
@@ -231,9 +247,22 @@ func makeNewAddressable() (*contract.Addressable, error) {
 
 // Stop shuts down the Service
 func (s *Service) Stop(force bool) error {
-	s.stopped = true
 	common.Driver.Stop(force)
+	if s.asyncPool != nil {
+		// the Driver has been stopped, drain the readings it pushed before
+		timeout := time.Duration(common.CurrentConfig.Service.Timeout) * time.Millisecond
+		if force {
+			timeout = 0
+		}
+		s.asyncPool.Stop(timeout)
+	}
 	autoevent.GetManager().StopAutoEvents()
+	autodiscovery.Stop()
+	if common.Publisher != nil {
+		if err := common.Publisher.Close(); err != nil {
+			common.LoggingClient.Error(fmt.Sprintf("failed to close the EventPublisher: %v", err))
+		}
+	}
 	return nil
 }
 
@@ -283,6 +312,12 @@ func NewService(serviceName string, serviceVersion string, confProfile string, c
 	svc.startTime = startTime
 	svc.svcInfo = &config.Service
 	common.Driver = proto
+	// a Driver supporting dynamic device discovery is the discoverer of the service
+	if discovery, ok := proto.(dsModels.ProtocolDiscovery); ok {
+		common.Discovery = discovery
+	} else {
+		common.Discovery = nil
+	}
 
 	return svc, nil
 }
@@ -0,0 +1,265 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2020 IOTech Ltd
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package device
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/internal/common"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testServiceName = "device-service-test"
+
+// discoveryDriver is a Driver which implements ProtocolDiscovery.
+type discoveryDriver struct {
+	devices []dsModels.DiscoveredDevice
+}
+
+func (d *discoveryDriver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.AsyncValues) error {
+	return nil
+}
+
+func (d *discoveryDriver) HandleReadCommands(deviceName string, protocols map[string]contract.ProtocolProperties, reqs []dsModels.CommandRequest) ([]*dsModels.CommandValue, error) {
+	return nil, fmt.Errorf("no resource")
+}
+
+func (d *discoveryDriver) HandleWriteCommands(deviceName string, protocols map[string]contract.ProtocolProperties, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
+	return fmt.Errorf("no resource")
+}
+
+func (d *discoveryDriver) Stop(force bool) error {
+	return nil
+}
+
+func (d *discoveryDriver) AddDevice(deviceName string, protocols map[string]contract.ProtocolProperties, adminState contract.AdminState) error {
+	return nil
+}
+
+func (d *discoveryDriver) UpdateDevice(deviceName string, protocols map[string]contract.ProtocolProperties, adminState contract.AdminState) error {
+	return nil
+}
+
+func (d *discoveryDriver) RemoveDevice(deviceName string, protocols map[string]contract.ProtocolProperties) error {
+	return nil
+}
+
+func (d *discoveryDriver) Discover() ([]dsModels.DiscoveredDevice, error) {
+	return d.devices, nil
+}
+
+// fakeCoreServices serves the Core Data and Core Metadata requests of the
+// service, the Devices added to Core Metadata are sent to the added channel.
+type fakeCoreServices struct {
+	server *httptest.Server
+	added  chan contract.Device
+}
+
+func newFakeCoreServices(t *testing.T) *fakeCoreServices {
+	f := &fakeCoreServices{added: make(chan contract.Device, 16)}
+	watcher := contract.ProvisionWatcher{
+		Name:        "watcher",
+		Identifiers: map[string]string{"address": "^10\\."},
+		Profile:     contract.DeviceProfile{Name: "discovered-profile"},
+		Service:     contract.DeviceService{Name: testServiceName},
+		AdminState:  contract.Unlocked,
+	}
+
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == clients.ApiPingRoute:
+			w.Write([]byte("pong"))
+		case strings.HasPrefix(req.URL.Path, clients.ApiDeviceServiceRoute+"/name/"):
+			json.NewEncoder(w).Encode(contract.DeviceService{Id: "ds-id", Name: testServiceName})
+		case strings.HasPrefix(req.URL.Path, clients.ApiProvisionWatcherRoute):
+			json.NewEncoder(w).Encode([]contract.ProvisionWatcher{watcher})
+		case req.Method == http.MethodPost && req.URL.Path == clients.ApiDeviceRoute:
+			var device contract.Device
+			if err := json.NewDecoder(req.Body).Decode(&device); err != nil {
+				t.Errorf("invalid Device added: %v", err)
+			}
+			f.added <- device
+			w.Write([]byte("device-id-" + device.Name))
+		case req.Method == http.MethodGet:
+			w.Write([]byte("[]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return f
+}
+
+// writeTestConfig writes the configuration of a service using the fake core
+// services to a new directory, the [Device.Discovery] settings are given as TOML.
+func writeTestConfig(t *testing.T, core *fakeCoreServices, discovery string) string {
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(core.server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "service-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := fmt.Sprintf(`[Writable]
+LogLevel = 'DEBUG'
+[Service]
+Host = "localhost"
+Port = 0
+ConnectRetries = 1
+Timeout = 1000
+[Clients]
+  [Clients.Data]
+  Protocol = "http"
+  Host = "%[1]s"
+  Port = %[2]s
+  Timeout = 1000
+  [Clients.Metadata]
+  Protocol = "http"
+  Host = "%[1]s"
+  Port = %[2]s
+  Timeout = 1000
+[Device]
+  DataTransform = true
+  MaxCmdOps = 128
+  MaxCmdValueLen = 256
+  [Device.Discovery]
+%[3]s
+[Logging]
+EnableRemote = false
+File = "%[4]s"
+`, host, port, discovery, filepath.Join(dir, "service.log"))
+	if err := ioutil.WriteFile(filepath.Join(dir, common.ConfigFileName), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// startTestService creates and starts a service with the Driver in the
+// configuration directory.
+func startTestService(t *testing.T, dir string, driver dsModels.ProtocolDriver) *Service {
+	svc = nil
+	s, err := NewService(testServiceName, "1.0.0", "", dir, "", driver)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	if err := s.Start(make(chan error, 1)); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	return s
+}
+
+func waitForAddedDevice(t *testing.T, core *fakeCoreServices) (contract.Device, bool) {
+	select {
+	case device := <-core.added:
+		return device, true
+	case <-time.After(2 * time.Second):
+		t.Error("the discovered Device is not added to Core Metadata")
+		return contract.Device{}, false
+	}
+}
+
+func TestNewServiceRegistersDiscovery(t *testing.T) {
+	core := newFakeCoreServices(t)
+	defer core.server.Close()
+	dir := writeTestConfig(t, core, "  Enabled = false")
+	defer os.RemoveAll(dir)
+
+	driver := &discoveryDriver{}
+	svc = nil
+	s, err := NewService(testServiceName, "1.0.0", "", dir, "", driver)
+	if assert.NoError(t, err) {
+		assert.Equal(t, driver, s.Discovery())
+	}
+
+	svc = nil
+	s, err = NewService(testServiceName, "1.0.0", "", dir, "", &driverWithoutDiscovery{})
+	if assert.NoError(t, err) {
+		assert.Nil(t, s.Discovery(), "a Driver without Discover isn't the discoverer of the service")
+	}
+}
+
+// driverWithoutDiscovery is a Driver which doesn't implement ProtocolDiscovery.
+type driverWithoutDiscovery struct {
+	dsModels.ProtocolDriver
+}
+
+func TestDiscoveryThroughRESTEndpoint(t *testing.T) {
+	core := newFakeCoreServices(t)
+	defer core.server.Close()
+	driver := &discoveryDriver{devices: []dsModels.DiscoveredDevice{
+		{Name: "blocked", Protocols: map[string]contract.ProtocolProperties{"other": {"address": "192.168.0.1"}}},
+		{Name: "discovered", Protocols: map[string]contract.ProtocolProperties{"other": {"address": "10.0.0.1"}}},
+	}}
+
+	dir := writeTestConfig(t, core, "  Enabled = false")
+	defer os.RemoveAll(dir)
+	s := startTestService(t, dir, driver)
+	defer s.Stop(true)
+
+	req := httptest.NewRequest(http.MethodPost, common.APIDiscoveryRoute, nil)
+	rr := httptest.NewRecorder()
+	s.controller.Router().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+
+	// only the Device matching the ProvisionWatcher is added
+	device, ok := waitForAddedDevice(t, core)
+	if ok {
+		assert.Equal(t, "discovered", device.Name)
+		assert.Equal(t, "discovered-profile", device.Profile.Name)
+		assert.Equal(t, testServiceName, device.Service.Name)
+	}
+	select {
+	case device := <-core.added:
+		t.Errorf("the Device %s doesn't match the ProvisionWatcher", device.Name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPeriodicDiscoveryStartedByService(t *testing.T) {
+	core := newFakeCoreServices(t)
+	defer core.server.Close()
+	driver := &discoveryDriver{devices: []dsModels.DiscoveredDevice{
+		{Name: "discovered", Protocols: map[string]contract.ProtocolProperties{"other": {"address": "10.0.0.1"}}},
+	}}
+
+	dir := writeTestConfig(t, core, "  Enabled = true\n  Interval = \"20ms\"")
+	defer os.RemoveAll(dir)
+	s := startTestService(t, dir, driver)
+
+	device, ok := waitForAddedDevice(t, core)
+	if ok {
+		assert.Equal(t, "discovered", device.Name)
+		assert.Equal(t, "discovered-profile", device.Profile.Name)
+	}
+
+	// the periodic discovery ends with the service
+	s.Stop(true)
+	time.Sleep(100 * time.Millisecond)
+	for len(core.added) > 0 {
+		<-core.added
+	}
+	select {
+	case <-core.added:
+		t.Error("the periodic discovery runs after the service stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+}